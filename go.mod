@@ -25,8 +25,10 @@ require (
 	github.com/skratchdot/open-golang v0.0.0-20200116055534-eef842397966
 	github.com/zmb3/spotify/v2 v2.3.1
 	go.etcd.io/bbolt v1.3.7
+	golang.org/x/crypto v0.31.0
 	golang.org/x/mod v0.17.0
 	golang.org/x/oauth2 v0.10.0
+	golang.org/x/term v0.27.0
 	golang.org/x/text v0.21.0
 )
 
@@ -59,11 +61,9 @@ require (
 	github.com/sahilm/fuzzy v0.1.0 // indirect
 	github.com/vcaesar/keycode v0.10.1 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
-	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
-	golang.org/x/term v0.27.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20230807174057-1744710a1577 // indirect
 	google.golang.org/grpc v1.58.3 // indirect
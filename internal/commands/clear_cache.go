@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/go-musicfox/spotifox/internal/httpcache"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/gookit/gcli/v2"
+	"github.com/pkg/errors"
+)
+
+func NewClearCacheCommand() *gcli.Command {
+	cmd := &gcli.Command{
+		Name:   "clear-cache",
+		UseFor: "Clear cached responses of Spotify Web API and cached lyrics",
+		Func: func(_ *gcli.Command, _ []string) error {
+			storage.DBManager = new(storage.LocalDBManager)
+			if err := httpcache.NewBoltStore().Clear(); err != nil {
+				return errors.Wrap(err, "clear cache failed, please quit spotifox first")
+			}
+			if err := storage.NewTable().DeleteTable(storage.LyricsCache{}); err != nil {
+				return errors.Wrap(err, "clear lyrics cache failed, please quit spotifox first")
+			}
+			fmt.Println("Cache cleared")
+			return nil
+		},
+	}
+	return cmd
+}
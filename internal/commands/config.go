@@ -18,6 +18,10 @@ func NewConfigCommand() *gcli.Command {
 		Func: func(_ *gcli.Command, _ []string) error {
 			var configPath = util.SetFgStyle(path.Join(utils.GetLocalDataDir(), types.AppIniFile), termenv.ANSICyan)
 			fmt.Printf("Loaded Configuration File:\n\t%s\n", configPath)
+			if GlobalOptions.Profile != "" {
+				var profilePath = util.SetFgStyle(utils.ProfileIniFile(GlobalOptions.Profile), termenv.ANSICyan)
+				fmt.Printf("Overridden by the Configuration File of Profile:\n\t%s\n", profilePath)
+			}
 			return nil
 		},
 	}
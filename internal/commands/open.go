@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/gookit/gcli/v2"
+)
+
+func NewOpenCommand() *gcli.Command {
+	cmd := &gcli.Command{
+		Name:     "open",
+		UseFor:   "Open a Spotify link or URI of track, album, playlist, artist, show or episode",
+		Examples: "{$fullCmd} spotify:album:4aawyAB9vmqN3uQ7FjRGTy\n  {$fullCmd} https://open.spotify.com/track/11dFghVXANMlKmJXsNCbNl",
+		Func: func(_ *gcli.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("missing link, e.g. spotifox open spotify:track:{id}")
+			}
+			if _, _, err := utils.ParseSpotifyLink(args[0]); err != nil {
+				return fmt.Errorf("%w: %s", err, args[0])
+			}
+			return startPlayer(args[0])
+		},
+	}
+	return cmd
+}
@@ -2,4 +2,6 @@ package commands
 
 var GlobalOptions struct {
 	PProfMode bool
+	// Profile selected at startup, the last used one if empty
+	Profile string
 }
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/secret"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// loadSecretKey loads the key sealing the stored credentials, the passphrase is asked on terminal if needed.
+// The store of key is recorded at the first time, so that the key is always loaded from it later
+func loadSecretKey() error {
+	if storage.DBManager == nil {
+		storage.DBManager = new(storage.LocalDBManager)
+	}
+
+	var (
+		dataDir  = utils.GetLocalDataDir()
+		store    = secret.Store(configs.ConfigRegistry.Main.SecretStore)
+		recorded = secret.Store(storage.SecretStore{}.Load())
+		opts     = secret.KeyOptions{
+			Store:          store,
+			Service:        types.AppName,
+			Account:        "storage",
+			KeyFile:        configs.ConfigRegistry.Main.SecretKeyFile,
+			PassphraseFile: filepath.Join(dataDir, types.SecretPassphraseFile),
+			Recorded:       recorded,
+		}
+	)
+	if opts.KeyFile == "" {
+		keyFile, err := defaultKeyFile()
+		if err != nil {
+			return err
+		}
+		opts.KeyFile = keyFile
+	}
+	if passphrase := os.Getenv(types.SecretPassphraseEnv); passphrase != "" {
+		opts.Passphrase = func() (string, error) { return passphrase, nil }
+	} else if store == secret.StorePassphrase || recorded == secret.StorePassphrase {
+		opts.Passphrase = askPassphrase
+	}
+
+	key, used, err := secret.LoadKey(opts)
+	if err != nil {
+		return errors.Wrap(err, "load secret key failed")
+	}
+	utils.Logger().Printf("[INFO] secret key is loaded from %s", used)
+	if recorded == "" {
+		if err = (storage.SecretStore{}).Store(string(used)); err != nil {
+			return errors.Wrap(err, "record secret store failed")
+		}
+	}
+	return secret.SetKey(key)
+}
+
+// defaultKeyFile the keyfile is kept out of the data dir, away from the sealed secrets.
+// The keyfile generated under the data dir before is moved there
+func defaultKeyFile() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if runtime.GOOS == "windows" {
+		// not roaming with the config dir
+		dir = os.Getenv("LOCALAPPDATA")
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.Wrap(err, "find dir of keyfile failed")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	keyFile := filepath.Join(dir, types.AppLocalDataDir, types.SecretKeyFile)
+
+	legacy := filepath.Join(utils.GetLocalDataDir(), types.SecretKeyFile)
+	if _, err := os.Stat(keyFile); !os.IsNotExist(err) {
+		return keyFile, nil
+	}
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		return keyFile, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return "", errors.Wrap(err, "create dir of keyfile failed")
+	}
+	if err = os.WriteFile(keyFile, data, 0600); err != nil {
+		return "", errors.Wrap(err, "move keyfile failed")
+	}
+	_ = os.Remove(legacy)
+	return keyFile, nil
+}
+
+func askPassphrase() (string, error) {
+	fmt.Print("Passphrase of stored credentials: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", errors.Wrap(err, "read passphrase failed")
+	}
+	return string(passphrase), nil
+}
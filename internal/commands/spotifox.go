@@ -7,6 +7,7 @@ import (
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/internal/ui"
 	"github.com/go-musicfox/spotifox/utils"
@@ -25,6 +26,25 @@ func NewPlayerCommand() *gcli.Command {
 }
 
 func runPlayer(_ *gcli.Command, _ []string) error {
+	return startPlayer("")
+}
+
+// startPlayer runs the player, startupLink is opened after initialized if not empty
+func startPlayer(startupLink string) error {
+	if GlobalOptions.Profile != "" {
+		if err := storage.CheckProfileName(GlobalOptions.Profile); err != nil {
+			return err
+		}
+	}
+
+	if err := loadSecretKey(); err != nil {
+		return err
+	}
+
+	// settings of the profile override the shared ones, so the profile is selected before reading them
+	profile := selectStartupProfile()
+	utils.LoadProfileIniConfig(profile)
+
 	if GlobalOptions.PProfMode {
 		go utils.PanicRecoverWrapper(true, func() {
 			panic(http.ListenAndServe(":"+strconv.Itoa(configs.ConfigRegistry.Main.PProfPort), nil))
@@ -50,6 +70,8 @@ func runPlayer(_ *gcli.Command, _ []string) error {
 		spotifox     = ui.NewSpotifox(model.NewApp(opts))
 		eventHandler = ui.NewEventHandler(spotifox)
 	)
+	spotifox.SetStartupLink(startupLink)
+	spotifox.SetStartupProfile(profile)
 	spotifox.App.With(
 		model.WithHook(spotifox.InitHook, spotifox.CloseHook),
 		model.WithMainMenu(ui.NewMainMenu(spotifox), &model.MenuItem{Title: "Spotifox"}),
@@ -62,3 +84,17 @@ func runPlayer(_ *gcli.Command, _ []string) error {
 
 	return spotifox.Run()
 }
+
+// selectStartupProfile the profile of `--profile`, or the last used one
+func selectStartupProfile() string {
+	profiles := storage.LoadProfiles()
+	name := GlobalOptions.Profile
+	if name == "" {
+		name = profiles.Last
+	}
+	if err := profiles.Use(name); err != nil {
+		utils.Logger().Printf("use profile %s failed: %+v", name, err)
+		name = profiles.Last
+	}
+	return name
+}
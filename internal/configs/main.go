@@ -8,6 +8,9 @@ type MainOptions struct {
 	ShowLyric        bool
 	LyricOffset      int
 	ShowLyricTrans   bool
+	LyricsDir        string
+	LyricsProviders  []string
+	LyricsHttpUrl    string
 	ShowNotify       bool
 	NotifyIcon       string
 	PProfPort        int
@@ -16,4 +19,7 @@ type MainOptions struct {
 	DualColumn       bool
 	LastfmKey        string
 	LastfmSecret     string
+	LastfmLoveSync   bool
+	SecretStore      string
+	SecretKeyFile    string
 }
@@ -2,6 +2,7 @@ package configs
 
 import (
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/anhoder/foxful-cli/model"
@@ -74,6 +75,8 @@ func NewRegistryWithDefault() *Registry {
 			PProfPort:        types.MainPProfPort,
 			AltScreen:        true,
 			EnableMouseEvent: true,
+			SecretStore:      types.SecretStoreAuto,
+			LyricsProviders:  strings.Split(types.LyricsProviders, ","),
 		},
 		Player: PlayerOptions{
 			Engine: types.BeepPlayer,
@@ -88,9 +91,16 @@ func NewRegistryWithDefault() *Registry {
 }
 
 func NewRegistryFromIniFile(filepath string) *Registry {
+	return NewRegistryFromIniFiles(filepath)
+}
+
+// NewRegistryFromIniFiles the items of later files override the ones of former files, files not exist are skipped
+func NewRegistryFromIniFiles(files ...string) *Registry {
 	registry := NewRegistryWithDefault()
 
-	if err := ini.LoadExists(filepath); err != nil {
+	// the items loaded before are dropped, so that it can be reloaded for another profile
+	ini.Reset()
+	if err := ini.LoadExists(files...); err != nil {
 		return registry
 	}
 
@@ -123,6 +133,7 @@ func NewRegistryFromIniFile(filepath string) *Registry {
 		registry.Spotify.ClientId = clientId
 	}
 	registry.Spotify.Cookie = ini.Get("spotify.cookie", "")
+	registry.Spotify.OAuthPort = ini.Int("spotify.oauthPort", types.SpotifyOAuthPort)
 
 	registry.Main.ShowTitle = ini.Bool("main.showTitle", true)
 	songFormat := SongFormat(ini.String("main.songFormat", string(Ogg320)))
@@ -140,16 +151,22 @@ func NewRegistryFromIniFile(filepath string) *Registry {
 	registry.Main.ShowLyric = ini.Bool("main.showLyric", true)
 	registry.Main.LyricOffset = ini.Int("main.lyricOffset", 0)
 	registry.Main.ShowLyricTrans = ini.Bool("main.showLyricTrans", false)
+	registry.Main.LyricsDir = ini.String("main.lyricsDir", "")
+	registry.Main.LyricsProviders = strings.Split(ini.String("main.lyricsProviders", types.LyricsProviders), ",")
+	registry.Main.LyricsHttpUrl = ini.String("main.lyricsHttpUrl", "")
 	registry.Main.ShowNotify = ini.Bool("main.enableNotify", true)
 	registry.Main.PProfPort = ini.Int("main.pprofPort", types.MainPProfPort)
 	registry.Main.AltScreen = ini.Bool("main.altScreen", true)
 	registry.Main.EnableMouseEvent = ini.Bool("main.enableMouseEvent", true)
 	registry.Main.DualColumn = ini.Bool("main.dualColumn", true)
+	registry.Main.SecretStore = ini.String("main.secretStore", types.SecretStoreAuto)
+	registry.Main.SecretKeyFile = ini.String("main.secretKeyFile", "")
 
 	registry.Main.LastfmKey = types.LastfmKey
 	if key := ini.String("main.lastfmKey"); key != "" {
 		registry.Main.LastfmKey = key
 	}
+	registry.Main.LastfmLoveSync = ini.Bool("main.lastfmLoveSync", false)
 	registry.Main.LastfmSecret = types.LastfmSecret
 	if secret := ini.String("main.lastfmSecret"); secret != "" {
 		registry.Main.LastfmSecret = secret
@@ -0,0 +1,33 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewRegistryFromIniFiles(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "spotifox.ini")
+	profile := filepath.Join(dir, "spotifox_work.ini")
+	if err := os.WriteFile(shared, []byte("[main]\nlanguage=zh\nlyricOffset=100\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(profile, []byte("[main]\nlyricOffset=200\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistryFromIniFiles(shared, profile)
+	if registry.Main.Language != "zh" {
+		t.Errorf("language = %q, want the shared one", registry.Main.Language)
+	}
+	if registry.Main.LyricOffset != 200 {
+		t.Errorf("lyricOffset = %d, want the one of profile", registry.Main.LyricOffset)
+	}
+
+	// reloaded without the profile, the items of profile are dropped
+	registry = NewRegistryFromIniFiles(shared, filepath.Join(dir, "spotifox_other.ini"))
+	if registry.Main.LyricOffset != 100 {
+		t.Errorf("lyricOffset = %d, want the shared one", registry.Main.LyricOffset)
+	}
+}
@@ -1,6 +1,7 @@
 package configs
 
 type SpotifyOptions struct {
-	ClientId string
-	Cookie   string
+	ClientId  string
+	Cookie    string
+	OAuthPort int
 }
@@ -0,0 +1,103 @@
+package connect
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Controller drives Spotify Connect devices of current user through the Web API.
+// spotifox itself isn't a Connect device, so playback is moved between spotifox
+// and other devices by replaying the track at the same position.
+type Controller struct {
+	client *spotify.Client
+}
+
+func NewController(client *spotify.Client) *Controller {
+	return &Controller{client: client}
+}
+
+// Devices returns all available Connect devices.
+func (c *Controller) Devices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	devices, err := c.client.PlayerDevices(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get player devices failed")
+	}
+	return devices, nil
+}
+
+// State returns the playback state of the active device, nil if no device is active.
+func (c *Controller) State(ctx context.Context) (*spotify.PlayerState, error) {
+	state, err := c.client.PlayerState(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get player state failed")
+	}
+	if state == nil || state.Device.ID == "" {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// TransferTo starts playing the track on the device from position.
+// If trackId is empty, the current playback of the active device is transferred.
+func (c *Controller) TransferTo(ctx context.Context, deviceId, trackId spotify.ID, position time.Duration) error {
+	var err error
+	if trackId == "" {
+		err = c.client.TransferPlayback(ctx, deviceId, true)
+	} else {
+		err = c.client.PlayOpt(ctx, &spotify.PlayOptions{
+			DeviceID:   &deviceId,
+			URIs:       []spotify.URI{spotify.URI("spotify:track:" + trackId)},
+			PositionMs: int(position.Milliseconds()),
+		})
+	}
+	return errors.Wrap(err, "transfer playback failed")
+}
+
+// Takeover pauses the active device and returns its state before pausing,
+// so that the caller can resume the same track locally.
+func (c *Controller) Takeover(ctx context.Context) (*spotify.PlayerState, error) {
+	state, err := c.State(ctx)
+	if err != nil || state == nil {
+		return state, err
+	}
+	if state.Playing {
+		if err = c.Pause(ctx, state.Device.ID); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func (c *Controller) Play(ctx context.Context, deviceId spotify.ID) error {
+	return errors.Wrap(c.client.PlayOpt(ctx, &spotify.PlayOptions{DeviceID: &deviceId}), "play failed")
+}
+
+func (c *Controller) Pause(ctx context.Context, deviceId spotify.ID) error {
+	return errors.Wrap(c.client.PauseOpt(ctx, &spotify.PlayOptions{DeviceID: &deviceId}), "pause failed")
+}
+
+func (c *Controller) Next(ctx context.Context, deviceId spotify.ID) error {
+	return errors.Wrap(c.client.NextOpt(ctx, &spotify.PlayOptions{DeviceID: &deviceId}), "skip to next failed")
+}
+
+func (c *Controller) Previous(ctx context.Context, deviceId spotify.ID) error {
+	return errors.Wrap(c.client.PreviousOpt(ctx, &spotify.PlayOptions{DeviceID: &deviceId}), "skip to previous failed")
+}
+
+// Seek moves the playback of the device to position, the negative position is treated as the start.
+func (c *Controller) Seek(ctx context.Context, deviceId spotify.ID, position time.Duration) error {
+	return errors.Wrap(c.client.SeekOpt(ctx, int(max(position, 0).Milliseconds()), &spotify.PlayOptions{DeviceID: &deviceId}), "seek failed")
+}
+
+// SetVolume sets volume of the device, percent will be clamped to [0, 100].
+func (c *Controller) SetVolume(ctx context.Context, deviceId spotify.ID, percent int) error {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	return errors.Wrap(c.client.VolumeOpt(ctx, percent, &spotify.PlayOptions{DeviceID: &deviceId}), "set volume failed")
+}
@@ -0,0 +1,166 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+type request struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+// newTestController starts a local stand-in for the Web API and records requests.
+func newTestController(t *testing.T, handler http.HandlerFunc) (*Controller, *[]request) {
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(body)})
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewController(spotify.New(srv.Client(), spotify.WithBaseURL(srv.URL+"/"))), &requests
+}
+
+func TestDevices(t *testing.T) {
+	c, _ := newTestController(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"devices":[{"id":"d1","is_active":true,"name":"Kitchen","type":"Speaker","volume_percent":40},{"id":"d2","name":"Phone","type":"Smartphone"}]}`)
+	})
+
+	devices, err := c.Devices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("expected 2 devices, got %d", len(devices))
+	}
+	if devices[0].ID != "d1" || !devices[0].Active || devices[0].Volume != 40 {
+		t.Fatalf("unexpected device: %+v", devices[0])
+	}
+}
+
+func TestStateWithoutActiveDevice(t *testing.T) {
+	c, _ := newTestController(t, nil)
+
+	state, err := c.State(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != nil {
+		t.Fatalf("expected nil state, got %+v", state)
+	}
+}
+
+func TestTransferTo(t *testing.T) {
+	c, requests := newTestController(t, nil)
+
+	if err := c.TransferTo(context.Background(), "d2", "t1", 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPut || req.path != "/me/player/play" || req.query != "device_id=d2" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	var body struct {
+		URIs       []string `json:"uris"`
+		PositionMs int      `json:"position_ms"`
+	}
+	if err := json.Unmarshal([]byte(req.body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.URIs) != 1 || body.URIs[0] != "spotify:track:t1" || body.PositionMs != 90000 {
+		t.Fatalf("unexpected body: %s", req.body)
+	}
+}
+
+func TestTransferWithoutTrack(t *testing.T) {
+	c, requests := newTestController(t, nil)
+
+	if err := c.TransferTo(context.Background(), "d2", "", 0); err != nil {
+		t.Fatal(err)
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPut || req.path != "/me/player" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestTakeover(t *testing.T) {
+	c, requests := newTestController(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, `{"device":{"id":"d1","is_active":true,"name":"Kitchen"},"is_playing":true,"progress_ms":12000,"item":{"id":"t1","name":"Song","duration_ms":200000}}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	state, err := c.Takeover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil || state.Item == nil || state.Item.ID != "t1" || state.Progress != 12000 {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*requests))
+	}
+	if req := (*requests)[1]; req.path != "/me/player/pause" || req.query != "device_id=d1" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestSetVolumeClamped(t *testing.T) {
+	c, requests := newTestController(t, nil)
+
+	if err := c.SetVolume(context.Background(), "d1", 120); err != nil {
+		t.Fatal(err)
+	}
+	if req := (*requests)[0]; req.path != "/me/player/volume" || req.query != "device_id=d1&volume_percent=100" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
+
+func TestRemoteError(t *testing.T) {
+	c, _ := newTestController(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":{"status":404,"message":"Device not found"}}`)
+	})
+
+	if err := c.Next(context.Background(), "gone"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSeekClamped(t *testing.T) {
+	c, requests := newTestController(t, nil)
+
+	if err := c.Seek(context.Background(), "d1", 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Seek(context.Background(), "d1", -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if req := (*requests)[0]; req.path != "/me/player/seek" || req.query != "device_id=d1&position_ms=90000" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if req := (*requests)[1]; req.query != "device_id=d1&position_ms=0" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+}
@@ -22,6 +22,7 @@ func runCLI() {
 	app.Description = types.AppDescription
 	app.GOptsBinder = func(gf *gcli.Flags) {
 		gf.BoolOpt(&commands.GlobalOptions.PProfMode, "pprof", "p", false, "enable PProf mode")
+		gf.StrOpt(&commands.GlobalOptions.Profile, "profile", "", "", "select the account profile, which is created if not exists")
 	}
 
 	utils.LoadIniConfig()
@@ -39,6 +40,8 @@ func runCLI() {
 	var playerCommand = commands.NewPlayerCommand()
 	app.Add(playerCommand)
 	app.Add(commands.NewConfigCommand())
+	app.Add(commands.NewOpenCommand())
+	app.Add(commands.NewClearCacheCommand())
 	app.DefaultCommand(playerCommand.Name)
 
 	app.Run()
@@ -0,0 +1,38 @@
+package httpcache
+
+import (
+	"encoding/json"
+
+	"github.com/go-musicfox/spotifox/internal/storage"
+)
+
+// BoltStore stores entries in local db
+type BoltStore struct{}
+
+func NewBoltStore() *BoltStore {
+	return &BoltStore{}
+}
+
+func (s *BoltStore) Get(key string) (*Entry, error) {
+	jsonStr, err := storage.NewTable().Get(storage.HttpCache{}, []byte(key))
+	if err != nil || len(jsonStr) == 0 {
+		return nil, err
+	}
+	var entry Entry
+	if err = json.Unmarshal(jsonStr, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *BoltStore) Set(key string, entry *Entry) error {
+	return storage.NewTable().Set(storage.HttpCache{}, []byte(key), entry)
+}
+
+func (s *BoltStore) DeletePrefix(prefix string) error {
+	return storage.NewTable().DeletePrefix(storage.HttpCache{}, []byte(prefix))
+}
+
+func (s *BoltStore) Clear() error {
+	return storage.NewTable().DeleteTable(storage.HttpCache{})
+}
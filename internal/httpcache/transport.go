@@ -0,0 +1,316 @@
+package httpcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderCache is set on responses served from cache, value is "fresh", "revalidated" or "stale"
+const HeaderCache = "X-Spotifox-Cache"
+
+// snapshotCheckInterval pages of a playlist share one snapshot_id check in the interval
+const snapshotCheckInterval = time.Minute
+
+// Rule TTL of the endpoints matching Pattern, zero TTL means never cached
+type Rule struct {
+	Pattern *regexp.Regexp
+	TTL     time.Duration
+}
+
+// DefaultRules TTLs of Spotify Web API endpoints, the first matched rule is used
+var DefaultRules = []Rule{
+	{Pattern: regexp.MustCompile(`^/v1/me/player`), TTL: 0},
+	{Pattern: regexp.MustCompile(`/contains$`), TTL: 0},
+	{Pattern: regexp.MustCompile(`^/v1/me/tracks`), TTL: time.Minute},
+	{Pattern: regexp.MustCompile(`^/v1/me/(albums|playlists|following)`), TTL: 5 * time.Minute},
+	{Pattern: regexp.MustCompile(`^/v1/me/top/`), TTL: time.Hour},
+	{Pattern: regexp.MustCompile(`^/v1/playlists/[^/]+`), TTL: 10 * time.Minute},
+	{Pattern: regexp.MustCompile(`^/v1/(albums|tracks|audio-features)`), TTL: 24 * time.Hour},
+	{Pattern: regexp.MustCompile(`^/v1/(artists|browse)`), TTL: time.Hour},
+	{Pattern: regexp.MustCompile(`^/v1/search`), TTL: 10 * time.Minute},
+}
+
+var playlistPathRegex = regexp.MustCompile(`^/v1/playlists/([^/]+)`)
+
+// Entry cached response
+type Entry struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// SnapshotId snapshot_id of the playlist when the entry is stored
+	SnapshotId string    `json:"snapshot_id,omitempty"`
+	StoredAt   time.Time `json:"stored_at"`
+}
+
+// Store persistence of entries
+type Store interface {
+	Get(key string) (*Entry, error)
+	Set(key string, entry *Entry) error
+	DeletePrefix(prefix string) error
+	Clear() error
+}
+
+type snapshot struct {
+	id        string
+	checkedAt time.Time
+}
+
+// Transport caches responses of GET requests, stale responses are validated by
+// ETag or snapshot_id of playlist, and served when the network is down
+type Transport struct {
+	Base  http.RoundTripper
+	Store Store
+	Rules []Rule
+	// Namespace separates the cache of different users
+	Namespace func() string
+
+	stale     atomic.Bool
+	l         sync.Mutex
+	snapshots map[string]snapshot
+	now       func() time.Time
+}
+
+func NewTransport(base http.RoundTripper, store Store) *Transport {
+	return &Transport{
+		Base:  base,
+		Store: store,
+		Rules: DefaultRules,
+	}
+}
+
+// Stale reports whether the last response was served from cache because of network error
+func (t *Transport) Stale() bool {
+	return t.stale.Load()
+}
+
+// Clear removes all cached responses
+func (t *Transport) Clear() error {
+	return t.Store.Clear()
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		res, err := t.base().RoundTrip(req)
+		if err == nil && res.StatusCode < http.StatusBadRequest {
+			t.invalidate(req.URL)
+		}
+		return res, err
+	}
+
+	ttl := t.ttlOf(req.URL.Path)
+	if ttl <= 0 || req.Header.Get("If-None-Match") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	key := t.keyOf(req.URL)
+	entry, _ := t.Store.Get(key)
+	if entry != nil && t.timeNow().Sub(entry.StoredAt) < ttl {
+		return entry.response(req, "fresh"), nil
+	}
+
+	if entry != nil && entry.SnapshotId != "" {
+		if id, err := t.snapshotOf(req); err == nil && id == entry.SnapshotId {
+			t.stale.Store(false)
+			t.refresh(key, entry)
+			return entry.response(req, "revalidated"), nil
+		}
+	}
+
+	outReq := req
+	if etag := entry.etag(); etag != "" {
+		outReq = req.Clone(req.Context())
+		outReq.Header.Set("If-None-Match", etag)
+	}
+	res, err := t.base().RoundTrip(outReq)
+	if err != nil {
+		if entry != nil && req.Context().Err() == nil {
+			t.stale.Store(true)
+			return entry.response(req, "stale"), nil
+		}
+		return nil, err
+	}
+	t.stale.Store(false)
+
+	if res.StatusCode == http.StatusNotModified && entry != nil {
+		_ = res.Body.Close()
+		t.refresh(key, entry)
+		return entry.response(req, "revalidated"), nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "read response body failed")
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry = &Entry{
+		StatusCode: res.StatusCode,
+		Header:     http.Header{},
+		Body:       body,
+		StoredAt:   t.timeNow(),
+	}
+	for _, h := range []string{"Content-Type", "ETag"} {
+		if v := res.Header.Get(h); v != "" {
+			entry.Header.Set(h, v)
+		}
+	}
+	if playlistPathRegex.MatchString(req.URL.Path) {
+		entry.SnapshotId = t.snapshotFromBody(req, body)
+	}
+	_ = t.Store.Set(key, entry)
+	return res, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) timeNow() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *Transport) ttlOf(path string) time.Duration {
+	for _, rule := range t.Rules {
+		if rule.Pattern.MatchString(path) {
+			return rule.TTL
+		}
+	}
+	return 0
+}
+
+func (t *Transport) keyOf(u *url.URL) string {
+	var namespace string
+	if t.Namespace != nil {
+		namespace = t.Namespace()
+	}
+	return namespace + " " + u.Host + u.RequestURI()
+}
+
+func (t *Transport) refresh(key string, entry *Entry) {
+	entry.StoredAt = t.timeNow()
+	_ = t.Store.Set(key, entry)
+}
+
+// invalidate removes the responses changed by the request, e.g. PUT /v1/me/tracks
+// removes the cached liked songs, POST /v1/playlists/{id}/tracks removes the playlist
+func (t *Transport) invalidate(u *url.URL) {
+	segments := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+	if len(segments) < 3 {
+		return
+	}
+	prefix := &url.URL{Host: u.Host, Path: "/" + strings.Join(segments[:3], "/")}
+	_ = t.Store.DeletePrefix(t.keyOf(prefix))
+	if segments[1] == "playlists" {
+		t.l.Lock()
+		delete(t.snapshots, segments[2])
+		t.l.Unlock()
+		_ = t.Store.DeletePrefix(t.keyOf(&url.URL{Host: u.Host, Path: "/v1/me/playlists"}))
+	}
+}
+
+// snapshotFromBody snapshot_id of the playlist, tracks of playlist don't contain it
+func (t *Transport) snapshotFromBody(req *http.Request, body []byte) string {
+	var playlist struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+	if err := json.Unmarshal(body, &playlist); err == nil && playlist.SnapshotId != "" {
+		t.setSnapshot(playlistPathRegex.FindStringSubmatch(req.URL.Path)[1], playlist.SnapshotId)
+		return playlist.SnapshotId
+	}
+	id, _ := t.snapshotOf(req)
+	return id
+}
+
+func (t *Transport) setSnapshot(playlistId, id string) {
+	t.l.Lock()
+	defer t.l.Unlock()
+	if t.snapshots == nil {
+		t.snapshots = make(map[string]snapshot)
+	}
+	t.snapshots[playlistId] = snapshot{id: id, checkedAt: t.timeNow()}
+}
+
+// snapshotOf fetches snapshot_id of the playlist requested, the result checked
+// in snapshotCheckInterval is reused
+func (t *Transport) snapshotOf(req *http.Request) (string, error) {
+	playlistId := playlistPathRegex.FindStringSubmatch(req.URL.Path)[1]
+
+	t.l.Lock()
+	s, ok := t.snapshots[playlistId]
+	t.l.Unlock()
+	if ok && t.timeNow().Sub(s.checkedAt) < snapshotCheckInterval {
+		return s.id, nil
+	}
+
+	u := *req.URL
+	u.Path, u.RawQuery = "/v1/playlists/"+playlistId, url.Values{"fields": {"snapshot_id"}}.Encode()
+	snapshotReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	snapshotReq.Header.Set("Authorization", req.Header.Get("Authorization"))
+	res, err := t.base().RoundTrip(snapshotReq)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf("get snapshot_id failed, status: %d", res.StatusCode)
+	}
+
+	var playlist struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&playlist); err != nil {
+		return "", errors.Wrap(err, "decode snapshot_id failed")
+	}
+	t.setSnapshot(playlistId, playlist.SnapshotId)
+	return playlist.SnapshotId, nil
+}
+
+func (e *Entry) etag() string {
+	if e == nil || e.Header == nil {
+		return ""
+	}
+	return e.Header.Get("ETag")
+}
+
+func (e *Entry) response(req *http.Request, state string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(HeaderCache, state)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,214 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memStore struct {
+	l       sync.Mutex
+	entries map[string]*Entry
+}
+
+func (s *memStore) Get(key string) (*Entry, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if e, ok := s.entries[key]; ok {
+		copied := *e
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (s *memStore) Set(key string, entry *Entry) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]*Entry)
+	}
+	copied := *entry
+	s.entries[key] = &copied
+	return nil
+}
+
+func (s *memStore) DeletePrefix(prefix string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	for k := range s.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.entries, k)
+		}
+	}
+	return nil
+}
+
+func (s *memStore) Clear() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.entries = nil
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestTransport() (*Transport, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	t := NewTransport(http.DefaultTransport, &memStore{})
+	t.now = clock.Now
+	return t, clock
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	t.Helper()
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("get %s: %v", url, err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	return string(body), res.Header.Get(HeaderCache)
+}
+
+func TestFreshResponseServedFromCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"name":"album"}`))
+	}))
+	defer srv.Close()
+
+	transport, _ := newTestTransport()
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL+"/v1/albums/a")
+	body, state := get(t, client, srv.URL+"/v1/albums/a")
+	if hits.Load() != 1 || state != "fresh" || body != `{"name":"album"}` {
+		t.Errorf("hits = %d, state = %q, body = %s", hits.Load(), state, body)
+	}
+
+	// not cached endpoint
+	get(t, client, srv.URL+"/v1/me/player")
+	get(t, client, srv.URL+"/v1/me/player")
+	if hits.Load() != 3 {
+		t.Errorf("player state should not be cached, hits = %d", hits.Load())
+	}
+}
+
+func TestStaleResponseRevalidatedByETag(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"name":"artist"}`))
+	}))
+	defer srv.Close()
+
+	transport, clock := newTestTransport()
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL+"/v1/artists/a")
+	clock.now = clock.now.Add(2 * time.Hour)
+	body, state := get(t, client, srv.URL+"/v1/artists/a")
+	if hits.Load() != 2 || state != "revalidated" || body != `{"name":"artist"}` {
+		t.Errorf("hits = %d, state = %q, body = %s", hits.Load(), state, body)
+	}
+}
+
+func TestPlaylistRevalidatedBySnapshot(t *testing.T) {
+	var (
+		snapshot   atomic.Value
+		trackHits  atomic.Int32
+		snapshotOk atomic.Int32
+	)
+	snapshot.Store("s1")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/playlists/p" {
+			snapshotOk.Add(1)
+			_, _ = w.Write([]byte(`{"snapshot_id":"` + snapshot.Load().(string) + `"}`))
+			return
+		}
+		trackHits.Add(1)
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	}))
+	defer srv.Close()
+
+	transport, clock := newTestTransport()
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL+"/v1/playlists/p/tracks?offset=0")
+	clock.now = clock.now.Add(time.Hour)
+	if _, state := get(t, client, srv.URL+"/v1/playlists/p/tracks?offset=0"); state != "revalidated" || trackHits.Load() != 1 {
+		t.Errorf("unchanged playlist, state = %q, track hits = %d", state, trackHits.Load())
+	}
+
+	snapshot.Store("s2")
+	clock.now = clock.now.Add(time.Hour)
+	if _, state := get(t, client, srv.URL+"/v1/playlists/p/tracks?offset=0"); state != "" || trackHits.Load() != 2 {
+		t.Errorf("changed playlist, state = %q, track hits = %d", state, trackHits.Load())
+	}
+}
+
+func TestStaleResponseServedWhenOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tracks":[]}`))
+	}))
+
+	transport, clock := newTestTransport()
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL+"/v1/search?q=a")
+	srv.Close()
+	clock.now = clock.now.Add(time.Hour)
+
+	body, state := get(t, client, srv.URL+"/v1/search?q=a")
+	if state != "stale" || body != `{"tracks":[]}` || !transport.Stale() {
+		t.Errorf("state = %q, body = %s, stale = %v", state, body, transport.Stale())
+	}
+
+	if _, err := client.Get(srv.URL + "/v1/search?q=b"); err == nil {
+		t.Error("uncached request should fail when offline")
+	}
+}
+
+func TestWriteInvalidatesCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hits.Add(1)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	transport, _ := newTestTransport()
+	client := &http.Client{Transport: transport}
+
+	get(t, client, srv.URL+"/v1/me/tracks?offset=0")
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/v1/me/tracks?ids=a", nil)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	get(t, client, srv.URL+"/v1/me/tracks?offset=0")
+	if hits.Load() != 2 {
+		t.Errorf("liked songs should be fetched again after saving track, hits = %d", hits.Load())
+	}
+}
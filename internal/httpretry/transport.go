@@ -0,0 +1,227 @@
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMaxRetries    = 5
+	DefaultTimeout       = 10 * time.Second
+	DefaultMaxRetryAfter = time.Minute
+	backoffBase          = 500 * time.Millisecond
+	backoffMax           = 10 * time.Second
+)
+
+// Reason why the request is retried
+type Reason string
+
+const (
+	ReasonRateLimited Reason = "rate_limited"
+	ReasonServerError Reason = "server_error"
+	ReasonTimeout     Reason = "timeout"
+)
+
+// Status of a retrying request
+type Status struct {
+	Reason  Reason
+	Attempt int
+	// Until the time of next attempt
+	Until time.Time
+}
+
+// Transport retries requests which are rate limited (429, honouring Retry-After),
+// failed by server errors (5xx) or timed out, with jittered exponential backoff.
+// Server errors and timeouts are only retried for idempotent methods.
+type Transport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	// Timeout of each attempt, zero means no timeout
+	Timeout time.Duration
+	// MaxRetryAfter responses asking to wait longer are returned directly
+	MaxRetryAfter time.Duration
+	// OnRetry is called before waiting for the next attempt
+	OnRetry func(req *http.Request, status Status)
+	// OnDone is called when a retried request finishes
+	OnDone func(req *http.Request)
+
+	retrying atomic.Int32
+	status   atomic.Value
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:          base,
+		MaxRetries:    DefaultMaxRetries,
+		Timeout:       DefaultTimeout,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+	}
+}
+
+// Retrying returns the status of the latest retrying request, ok is false if no request is retrying
+func (t *Transport) Retrying() (status Status, ok bool) {
+	if t.retrying.Load() <= 0 {
+		return Status{}, false
+	}
+	status, _ = t.status.Load().(Status)
+	return status, true
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var retried bool
+	defer func() {
+		if retried {
+			t.retrying.Add(-1)
+			if t.OnDone != nil {
+				t.OnDone(req)
+			}
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		outReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			outReq = req.Clone(req.Context())
+			outReq.Body = body
+		}
+
+		res, err := t.roundTrip(outReq)
+		wait, reason := t.retryAfter(req, res, err, attempt)
+		if reason == "" {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+			_ = res.Body.Close()
+		}
+
+		if !retried {
+			retried = true
+			t.retrying.Add(1)
+		}
+		status := Status{Reason: reason, Attempt: attempt + 1, Until: time.Now().Add(wait)}
+		t.status.Store(status)
+		if t.OnRetry != nil {
+			t.OnRetry(req, status)
+		}
+		if err := t.wait(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Timeout <= 0 {
+		return base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.Timeout)
+	res, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// retryAfter returns how long to wait before next attempt, reason is empty if no need to retry
+func (t *Transport) retryAfter(req *http.Request, res *http.Response, err error, attempt int) (time.Duration, Reason) {
+	if attempt >= t.MaxRetries || req.Context().Err() != nil {
+		return 0, ""
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return 0, ""
+	}
+
+	if err != nil {
+		if isIdempotent(req.Method) && isTimeout(err) {
+			return backoff(attempt), ReasonTimeout
+		}
+		return 0, ""
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		wait := time.Second
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		if t.MaxRetryAfter > 0 && wait > t.MaxRetryAfter {
+			return 0, ""
+		}
+		return wait, ReasonRateLimited
+	case res.StatusCode >= http.StatusInternalServerError && res.StatusCode != http.StatusNotImplemented:
+		if isIdempotent(req.Method) {
+			return backoff(attempt), ReasonServerError
+		}
+	}
+	return 0, ""
+}
+
+func (t *Transport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff exponential backoff with jitter in [0.5, 1.5)
+func backoff(attempt int) time.Duration {
+	d := backoffBase << attempt
+	if d > backoffMax || d <= 0 {
+		d = backoffMax
+	}
+	return time.Duration(float64(d) * (0.5 + rand.Float64()))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// cancelBody cancels the context of attempt after the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
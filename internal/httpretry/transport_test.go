@@ -0,0 +1,172 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestTransport(waits *[]time.Duration) *Transport {
+	t := NewTransport(http.DefaultTransport)
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return t
+}
+
+func TestRateLimitedHonoursRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var (
+		waits     []time.Duration
+		transport = newTestTransport(&waits)
+		statuses  []Status
+	)
+	transport.OnRetry = func(_ *http.Request, status Status) {
+		if _, ok := transport.Retrying(); !ok {
+			t.Error("transport should be retrying")
+		}
+		statuses = append(statuses, status)
+	}
+
+	res, err := (&http.Client{Transport: transport}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Errorf("status = %d, hits = %d", res.StatusCode, hits.Load())
+	}
+	if len(waits) != 2 || waits[0] != 3*time.Second || waits[1] != 3*time.Second {
+		t.Errorf("waits = %v", waits)
+	}
+	if len(statuses) != 2 || statuses[1].Reason != ReasonRateLimited || statuses[1].Attempt != 2 {
+		t.Errorf("statuses = %+v", statuses)
+	}
+	if _, ok := transport.Retrying(); ok {
+		t.Error("transport should not be retrying after done")
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	res, err := (&http.Client{Transport: newTestTransport(&waits)}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusTooManyRequests || len(waits) != 0 {
+		t.Errorf("status = %d, waits = %v", res.StatusCode, waits)
+	}
+}
+
+func TestServerErrorBackoff(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var (
+		waits     []time.Duration
+		transport = newTestTransport(&waits)
+		client    = &http.Client{Transport: transport}
+	)
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusBadGateway || int(hits.Load()) != transport.MaxRetries+1 {
+		t.Errorf("status = %d, hits = %d", res.StatusCode, hits.Load())
+	}
+	for i, wait := range waits {
+		base := backoffBase << i
+		if base > backoffMax {
+			base = backoffMax
+		}
+		if wait < base/2 || wait >= base*3/2 {
+			t.Errorf("wait %d = %v, expected jitter around %v", i, wait, base)
+		}
+	}
+
+	// POST isn't idempotent
+	hits.Store(0)
+	res, err = client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if hits.Load() != 1 {
+		t.Errorf("POST should not be retried, hits = %d", hits.Load())
+	}
+}
+
+func TestTimeoutRetried(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var (
+		waits     []time.Duration
+		transport = newTestTransport(&waits)
+	)
+	transport.Timeout = 50 * time.Millisecond
+
+	res, err := (&http.Client{Transport: transport}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK || hits.Load() != 2 || len(waits) != 1 {
+		t.Errorf("status = %d, hits = %d, waits = %v", res.StatusCode, hits.Load(), waits)
+	}
+}
+
+func TestCanceledWhileWaiting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := NewTransport(http.DefaultTransport)
+	transport.OnRetry = func(_ *http.Request, _ Status) {
+		cancel()
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	_, err := (&http.Client{Transport: transport}).Do(req)
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("request should be canceled while waiting, err = %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 package lastfm
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
@@ -19,11 +21,13 @@ type AuthInvalid struct {
 }
 
 type Client struct {
-	api *lastfmgo.Api
+	api   *lastfmgo.Api
+	queue *ScrobbleQueue
 }
 
 func NewClient() *Client {
 	client := &Client{}
+	client.queue = newScrobbleQueue(client)
 	if configs.ConfigRegistry.Main.LastfmKey == "" || configs.ConfigRegistry.Main.LastfmSecret == "" {
 		err := errors.New(locale.MustT("lastfm_config_empty"))
 		_, _ = client.errorHandle(err)
@@ -69,6 +73,19 @@ func (c *Client) SetSession(session string) {
 	if c.api != nil {
 		c.api.SetSession(session)
 	}
+	if session != "" {
+		c.queue.Wake()
+	}
+}
+
+// Authed whether the client can submit to Last.fm
+func (c *Client) Authed() bool {
+	return c.api != nil && c.api.GetSessionKey() != ""
+}
+
+// ScrobbleQueue the queue of scrobbles not submitted
+func (c *Client) ScrobbleQueue() *ScrobbleQueue {
+	return c.queue
 }
 
 func (c *Client) GetSession(token string) (sessionKey string, err error) {
@@ -117,6 +134,73 @@ func (c *Client) Scrobble(args map[string]interface{}) error {
 	return err
 }
 
+// ScrobbleBatch submits at most 50 scrobbles in one request, the error isn't retried here
+func (c *Client) ScrobbleBatch(list []storage.PendingScrobble) error {
+	if c.api == nil {
+		return errors.New(locale.MustT("lastfm_config_empty"))
+	}
+	if c.api.GetSessionKey() == "" {
+		_, err := c.errorHandle(errors.New("empty session key"))
+		return err
+	}
+	_, err := c.api.Track.Scrobble(scrobbleBatchArgs(list))
+	_, err = c.errorHandle(err)
+	return err
+}
+
+// Love loves or unloves the track, the server errors are retried at most LastfmLoveMaxAttempts times
+func (c *Client) Love(artist, track string, loveOrNot bool) error {
+	if c.api == nil {
+		return errors.New(locale.MustT("lastfm_config_empty"))
+	}
+	if c.api.GetSessionKey() == "" {
+		_, err := c.errorHandle(errors.New("empty session key"))
+		return err
+	}
+	args := map[string]interface{}{"artist": artist, "track": track}
+	for attempt := 1; ; attempt++ {
+		var err error
+		if loveOrNot {
+			err = c.api.Track.Love(args)
+		} else {
+			err = c.api.Track.UnLove(args)
+		}
+
+		var retry bool
+		if retry, err = c.errorHandle(err); !retry || attempt >= types.LastfmLoveMaxAttempts {
+			return err
+		}
+		time.Sleep(types.LastfmLoveRetryInterval * time.Duration(attempt))
+	}
+}
+
+// LovedTracks all the loved tracks of user, the latest first
+func (c *Client) LovedTracks(ctx context.Context, user string) ([]LovedTrack, error) {
+	if c.api == nil {
+		return nil, errors.New(locale.MustT("lastfm_config_empty"))
+	}
+	var tracks []LovedTrack
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res, err := c.api.User.GetLovedTracks(map[string]interface{}{
+			"user":  user,
+			"limit": types.LastfmLovedTracksPageSize,
+			"page":  page,
+		})
+		if _, err = c.errorHandle(err); err != nil {
+			return nil, err
+		}
+		for _, t := range res.Tracks {
+			tracks = append(tracks, LovedTrack{Artist: t.Artist.Name, Track: t.Name})
+		}
+		if page >= res.TotalPages || len(res.Tracks) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
 func (c *Client) GetUserInfo(args map[string]interface{}) (lastfmgo.UserGetInfo, error) {
 	if c.api == nil {
 		return lastfmgo.UserGetInfo{}, errors.New(locale.MustT("lastfm_config_empty"))
@@ -141,7 +225,18 @@ const (
 	ReportPhaseComplete
 )
 
-func Report(client *Client, phase ReportPhase, song spotify.FullTrack, passedTime time.Duration) {
+// Scrobblable the rules of Last.fm: the track is longer than 30 seconds,
+// and it has been listened for half of its duration or 4 minutes
+func Scrobblable(duration, listened time.Duration) bool {
+	if duration <= types.LastfmScrobbleMinDuration {
+		return false
+	}
+	return listened >= duration/2 || listened >= types.LastfmScrobbleEnoughListened
+}
+
+// Report reports the now playing at start, and scrobbles the track at complete,
+// listened is the time really listened and startedAt is when the track started playing
+func Report(client *Client, phase ReportPhase, song spotify.FullTrack, listened time.Duration, startedAt time.Time) {
 	switch phase {
 	case ReportPhaseStart:
 		go func(song spotify.FullTrack) {
@@ -153,18 +248,20 @@ func Report(client *Client, phase ReportPhase, song spotify.FullTrack, passedTim
 			})
 		}(song)
 	case ReportPhaseComplete:
-		duration := song.TimeDuration().Seconds()
-		passedSeconds := passedTime.Seconds()
-		if passedSeconds >= duration/2 {
-			go func(song spotify.FullTrack, duration float64) {
-				_ = client.Scrobble(map[string]interface{}{
-					"artist":    utils.ArtistNameStrOfSong(&song),
-					"track":     song.Name,
-					"album":     song.Album.Name,
-					"timestamp": time.Now().Unix(),
-					"duration":  duration,
-				})
-			}(song, duration)
+		duration := song.TimeDuration()
+		// kept in the queue if offline or the session is rejected for a while, it's submitted once the session is available.
+		// Nothing is queued if Last.fm isn't configured or authed by the profile, they wouldn't be submitted
+		if client.Authed() && Scrobblable(duration, listened) {
+			err := client.queue.Add(storage.PendingScrobble{
+				Artist:    utils.ArtistNameStrOfSong(&song),
+				Track:     song.Name,
+				Album:     song.Album.Name,
+				Timestamp: startedAt.Unix(),
+				Duration:  int(duration.Seconds()),
+			})
+			if err != nil {
+				utils.Logger().Printf("[ERROR] add scrobble failed: %+v", err)
+			}
 		}
 	}
 }
@@ -0,0 +1,73 @@
+package lastfm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Listening tracks the time really listened of the playing track for scrobbling, the pauses and seeks are excluded.
+// It's safe for concurrent use, the positions are observed by the player while seeks and track changes come from UI
+type Listening struct {
+	l         sync.Mutex
+	song      spotify.FullTrack
+	startedAt time.Time
+	reported  bool
+	total     time.Duration
+	lastPos   time.Duration
+}
+
+// Start begins tracking the song started playing at startedAt
+func (l *Listening) Start(song spotify.FullTrack, startedAt time.Time) {
+	l.l.Lock()
+	defer l.l.Unlock()
+	l.song, l.startedAt, l.reported = song, startedAt, false
+	l.total, l.lastPos = 0, 0
+}
+
+// Observe counts the progress since last position, the jump larger than a tick is a seek
+func (l *Listening) Observe(pos time.Duration) {
+	l.l.Lock()
+	defer l.l.Unlock()
+	delta := pos - l.lastPos
+	l.lastPos = pos
+	if delta > 0 && delta <= types.ListenedMaxTickGap {
+		l.total += delta
+	}
+}
+
+// Seeked moves the position without counting
+func (l *Listening) Seeked(pos time.Duration) {
+	l.l.Lock()
+	defer l.l.Unlock()
+	l.lastPos = pos
+}
+
+// Listened the time really listened of the song
+func (l *Listening) Listened() time.Duration {
+	l.l.Lock()
+	defer l.l.Unlock()
+	return l.total
+}
+
+// Complete marks the song reported, ok is false if it has been reported or nothing is started,
+// so that a song is only reported once even if it's completed and skipped at the same time
+func (l *Listening) Complete() (song spotify.FullTrack, listened time.Duration, startedAt time.Time, ok bool) {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if l.reported || l.song.ID == "" || l.startedAt.IsZero() {
+		return spotify.FullTrack{}, 0, time.Time{}, false
+	}
+	l.reported = true
+	return l.song, l.total, l.startedAt, true
+}
+
+// Reset clears the song tracked
+func (l *Listening) Reset() {
+	l.l.Lock()
+	defer l.l.Unlock()
+	l.song, l.startedAt, l.reported = spotify.FullTrack{}, time.Time{}, false
+	l.total, l.lastPos = 0, 0
+}
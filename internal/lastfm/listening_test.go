@@ -0,0 +1,82 @@
+package lastfm
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestListening(t *testing.T) {
+	var l Listening
+	if _, _, _, ok := l.Complete(); ok {
+		t.Fatal("expected nothing to complete before start")
+	}
+
+	song := spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: "1", Name: "T"}}
+	started := time.Now()
+	l.Start(song, started)
+	for pos := 200 * time.Millisecond; pos <= 2*time.Second; pos += 200 * time.Millisecond {
+		l.Observe(pos)
+	}
+	if got := l.Listened(); got != 2*time.Second {
+		t.Fatalf("listened = %s, expected 2s", got)
+	}
+
+	// paused: the position is reported unchanged
+	l.Observe(2 * time.Second)
+	l.Observe(2 * time.Second)
+	// seek jumps forward by the player, not counted
+	l.Observe(time.Minute)
+	// seek by user, the next tick continues from the new position
+	l.Seeked(10 * time.Second)
+	l.Observe(10*time.Second + 200*time.Millisecond)
+	// seek backward
+	l.Observe(5 * time.Second)
+	if got := l.Listened(); got != 2*time.Second+200*time.Millisecond {
+		t.Fatalf("listened = %s, expected 2.2s", got)
+	}
+
+	gotSong, listened, startedAt, ok := l.Complete()
+	if !ok || gotSong.ID != song.ID || listened != 2*time.Second+200*time.Millisecond || !startedAt.Equal(started) {
+		t.Fatalf("complete = %v %s %s %v", gotSong.ID, listened, startedAt, ok)
+	}
+	if _, _, _, ok = l.Complete(); ok {
+		t.Fatal("expected reported only once")
+	}
+
+	l.Start(song, started)
+	l.Observe(200 * time.Millisecond)
+	l.Reset()
+	if _, _, _, ok = l.Complete(); ok || l.Listened() != 0 {
+		t.Fatal("expected cleared after reset")
+	}
+}
+
+func TestListeningCompleteOnce(t *testing.T) {
+	var l Listening
+	l.Start(spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: "1"}}, time.Now())
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Observe(200 * time.Millisecond)
+			if _, _, _, ok := l.Complete(); ok {
+				mu.Lock()
+				completed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if completed != 1 {
+		t.Errorf("completed %d times, expected once", completed)
+	}
+}
@@ -0,0 +1,87 @@
+package lastfm
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// LovedTrack the track loved on Last.fm
+type LovedTrack struct {
+	Artist string
+	Track  string
+}
+
+// SearchQuery the query to search the track on Spotify, only the first one of
+// the artists joined by comma (as scrobbled by spotifox) is searched
+func (t LovedTrack) SearchQuery() string {
+	unquote := strings.NewReplacer(`"`, "")
+	artist, _, _ := strings.Cut(t.Artist, ",")
+	return `track:"` + unquote.Replace(t.Track) + `" artist:"` + unquote.Replace(strings.TrimSpace(artist)) + `"`
+}
+
+// LoveMatch the loved track matched with the search results of Spotify
+type LoveMatch struct {
+	Loved LovedTrack
+	// Match the track whose name and artist are the same, nil if not found
+	Match *spotify.FullTrack
+	// Candidates the similar tracks to be reviewed if no match
+	Candidates []spotify.FullTrack
+}
+
+// Ambiguous whether the match should be reviewed by user
+func (m LoveMatch) Ambiguous() bool {
+	return m.Match == nil && len(m.Candidates) > 0
+}
+
+// versionSuffix the suffix of versions, e.g. " - Remastered 2011", " (feat. Someone)"
+var versionSuffix = regexp.MustCompile(`(?i)(\s+-\s+.*(remaster|version|edit|mix|live|mono|stereo).*|\s*[(\[](feat|ft|with|remaster|.*version|.*edit|.*mix)[^)\]]*[)\]])$`)
+
+// normalizeName lowercases the name and strips the version suffixes and punctuations
+func normalizeName(name string) string {
+	name = strings.TrimSpace(name)
+	for {
+		stripped := versionSuffix.ReplaceAllString(name, "")
+		if stripped == name {
+			break
+		}
+		name = strings.TrimSpace(stripped)
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// MatchLovedTrack finds the track in results whose name and one of the artists (or all of them joined) are the same as the loved one,
+// the results with the same name or artist are returned as candidates if not found
+func MatchLovedTrack(loved LovedTrack, results []spotify.FullTrack) LoveMatch {
+	var (
+		match  = LoveMatch{Loved: loved}
+		name   = normalizeName(loved.Track)
+		artist = normalizeName(loved.Artist)
+	)
+	for i, track := range results {
+		sameName := normalizeName(track.Name) == name
+		var (
+			sameArtist bool
+			names      []string
+		)
+		for _, a := range track.Artists {
+			if normalizeName(a.Name) == artist {
+				sameArtist = true
+			}
+			names = append(names, a.Name)
+		}
+		sameArtist = sameArtist || normalizeName(strings.Join(names, ",")) == artist
+		if sameName && sameArtist {
+			match.Match, match.Candidates = &results[i], nil
+			return match
+		}
+		if sameName || sameArtist {
+			match.Candidates = append(match.Candidates, track)
+		}
+	}
+	return match
+}
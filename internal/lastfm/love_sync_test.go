@@ -0,0 +1,75 @@
+package lastfm
+
+import (
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func track(id, name string, artists ...string) spotify.FullTrack {
+	t := spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: spotify.ID(id), Name: name}}
+	for _, a := range artists {
+		t.Artists = append(t.Artists, spotify.SimpleArtist{Name: a})
+	}
+	return t
+}
+
+func TestNormalizeName(t *testing.T) {
+	cases := map[string]string{
+		"Here Comes the Sun - Remastered 2009": "here comes the sun",
+		"Don't Stop Me Now":                    "don t stop me now",
+		"Song (feat. Someone)":                 "song",
+		"Numb (Live Version)":                  "numb",
+		"Sigur Rós":                            "sigur rós",
+	}
+	for name, expected := range cases {
+		if got := normalizeName(name); got != expected {
+			t.Errorf("normalizeName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestMatchLovedTrack(t *testing.T) {
+	loved := LovedTrack{Artist: "The Beatles", Track: "Here Comes The Sun"}
+
+	match := MatchLovedTrack(loved, []spotify.FullTrack{
+		track("1", "Here Comes the Sun (Cover)", "Someone"),
+		track("2", "Here Comes The Sun - Remastered 2009", "The Beatles"),
+	})
+	if match.Match == nil || match.Match.ID != "2" || match.Ambiguous() {
+		t.Errorf("expected exact match 2, got %+v", match)
+	}
+
+	match = MatchLovedTrack(loved, []spotify.FullTrack{
+		track("1", "Here Comes the Sun", "Nina Simone"),
+		track("2", "Something", "The Beatles"),
+		track("3", "Other", "Other"),
+	})
+	if match.Match != nil || !match.Ambiguous() || len(match.Candidates) != 2 {
+		t.Errorf("expected 2 candidates, got %+v", match)
+	}
+
+	// the artists joined as scrobbled by spotifox
+	match = MatchLovedTrack(LovedTrack{Artist: "A,B", Track: "Duet"}, []spotify.FullTrack{
+		track("1", "Duet", "A", "B"),
+	})
+	if match.Match == nil || match.Match.ID != "1" {
+		t.Errorf("expected match of joined artists, got %+v", match)
+	}
+
+	match = MatchLovedTrack(loved, nil)
+	if match.Match != nil || match.Ambiguous() {
+		t.Errorf("expected not found, got %+v", match)
+	}
+}
+
+func TestLovedTrackSearchQuery(t *testing.T) {
+	q := LovedTrack{Artist: "A", Track: `Say "Hi"`}.SearchQuery()
+	if q != `track:"Say Hi" artist:"A"` {
+		t.Errorf("query = %s", q)
+	}
+	q = LovedTrack{Artist: "A, B", Track: "T"}.SearchQuery()
+	if q != `track:"T" artist:"A"` {
+		t.Errorf("query = %s", q)
+	}
+}
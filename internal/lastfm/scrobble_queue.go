@@ -0,0 +1,171 @@
+package lastfm
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+	lastfmgo "github.com/shkh/lastfm-go"
+)
+
+// ScrobbleQueue keeps the scrobbles in bbolt until they're submitted, so that the tracks played offline
+// aren't lost. The scrobbles are submitted in batches, and retried with backoff on failure
+type ScrobbleQueue struct {
+	client *Client
+	flushL sync.Mutex
+	wake   chan struct{}
+}
+
+func newScrobbleQueue(client *Client) *ScrobbleQueue {
+	return &ScrobbleQueue{
+		client: client,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Add saves the scrobble and wakes the queue to submit it
+func (q *ScrobbleQueue) Add(s storage.PendingScrobble) error {
+	if err := (storage.PendingScrobbles{}).Add(&s); err != nil {
+		return errors.Wrap(err, "save scrobble failed")
+	}
+	q.Wake()
+	return nil
+}
+
+// Wake submits the pending scrobbles as soon as possible
+func (q *ScrobbleQueue) Wake() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Clear drops the pending scrobbles, e.g. the auth of profile is cleared
+func (q *ScrobbleQueue) Clear() error {
+	if err := (storage.PendingScrobbles{}).Clear(); err != nil {
+		return errors.Wrap(err, "clear pending scrobbles failed")
+	}
+	return nil
+}
+
+// Pending the count of scrobbles not submitted
+func (q *ScrobbleQueue) Pending() int {
+	return storage.PendingScrobbles{}.Count()
+}
+
+// Flush submits all the pending scrobbles, the batch rejected by Last.fm is dropped.
+// The submitted ones are removed even if an error is returned.
+// The queue of the profile when flush starts is flushed, it stops if the profile is switched
+func (q *ScrobbleQueue) Flush() (submitted int, err error) {
+	q.flushL.Lock()
+	defer q.flushL.Unlock()
+
+	profile := storage.CurProfile()
+	queue := storage.PendingScrobbles{Profile: profile}
+	for {
+		if storage.CurProfile() != profile {
+			// the rest is submitted by the session of profile when it's used again
+			return submitted, nil
+		}
+		list, err := queue.Head(types.LastfmScrobbleBatchSize)
+		if err != nil {
+			return submitted, errors.Wrap(err, "read pending scrobbles failed")
+		}
+		if len(list) == 0 {
+			return submitted, nil
+		}
+
+		if err = q.client.ScrobbleBatch(list); err != nil {
+			if isRetryable(err) {
+				return submitted, err
+			}
+			utils.Logger().Printf("[ERROR] drop %d scrobbles rejected by lastfm: %+v", len(list), err)
+		} else {
+			submitted += len(list)
+		}
+		if err = queue.Remove(list); err != nil {
+			return submitted, errors.Wrap(err, "remove submitted scrobbles failed")
+		}
+	}
+}
+
+// Run flushes the queue when it's woken, and retries with backoff after failure until ctx is done
+func (q *ScrobbleQueue) Run(ctx context.Context) {
+	var (
+		failures int
+		timer    = time.NewTimer(0) // flush the scrobbles left by last run
+	)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+		}
+		if !q.client.Authed() {
+			continue
+		}
+
+		if _, err := q.Flush(); err != nil {
+			failures++
+			backoff := retryBackoff(failures)
+			utils.Logger().Printf("[WARN] submit scrobbles failed, retry after %s: %+v", backoff, err)
+			timer.Reset(backoff)
+			continue
+		}
+		failures = 0
+	}
+}
+
+// retryBackoff doubles the delay after each failure, up to LastfmScrobbleRetryMax
+func retryBackoff(failures int) time.Duration {
+	backoff := types.LastfmScrobbleRetryMin
+	for i := 1; i < failures && backoff < types.LastfmScrobbleRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, types.LastfmScrobbleRetryMax)
+}
+
+// isRetryable whether the scrobbles should be kept for retry, they're rejected only if Last.fm says so
+func isRetryable(err error) bool {
+	var lastfmErr *lastfmgo.LastfmError
+	if !errors.As(err, &lastfmErr) {
+		return true
+	}
+	switch lastfmErr.Code {
+	case 8, 11, 16, 29: // operation failed, service offline, temporarily unavailable, rate limit
+		return true
+	}
+	return false
+}
+
+// scrobbleBatchArgs the args of track.scrobble, which are indexed like artist[0]
+func scrobbleBatchArgs(list []storage.PendingScrobble) map[string]interface{} {
+	var artists, tracks, albums, timestamps, durations []string
+	for _, s := range list {
+		artists = append(artists, s.Artist)
+		tracks = append(tracks, s.Track)
+		albums = append(albums, s.Album)
+		timestamps = append(timestamps, strconv.FormatInt(s.Timestamp, 10))
+		durations = append(durations, strconv.Itoa(s.Duration))
+	}
+	return map[string]interface{}{
+		"artist":    artists,
+		"track":     tracks,
+		"album":     albums,
+		"timestamp": timestamps,
+		"duration":  durations,
+	}
+}
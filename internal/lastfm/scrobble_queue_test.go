@@ -0,0 +1,129 @@
+package lastfm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/types"
+	lastfmgo "github.com/shkh/lastfm-go"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	if b := retryBackoff(1); b != types.LastfmScrobbleRetryMin {
+		t.Errorf("backoff = %s, expected %s", b, types.LastfmScrobbleRetryMin)
+	}
+	if b := retryBackoff(2); b != types.LastfmScrobbleRetryMin*2 {
+		t.Errorf("backoff = %s, expected doubled", b)
+	}
+	if b := retryBackoff(100); b != types.LastfmScrobbleRetryMax {
+		t.Errorf("backoff = %s, expected %s", b, types.LastfmScrobbleRetryMax)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{err: errors.New("dial tcp: i/o timeout"), retryable: true},
+		{err: AuthInvalid{&lastfmgo.LastfmError{Code: 9}}, retryable: true},
+		{err: &lastfmgo.LastfmError{Code: 16}, retryable: true},
+		{err: &lastfmgo.LastfmError{Code: 29}, retryable: true},
+		{err: &lastfmgo.LastfmError{Code: 6}, retryable: false},
+		{err: &lastfmgo.LastfmError{Code: lastfmgo.ErrorInvalidTypeOfArgument}, retryable: false},
+	}
+	for _, c := range cases {
+		if isRetryable(c.err) != c.retryable {
+			t.Errorf("%v retryable = %v, expected %v", c.err, !c.retryable, c.retryable)
+		}
+	}
+}
+
+func TestScrobbleBatchArgs(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix()
+	args := scrobbleBatchArgs([]storage.PendingScrobble{
+		{Artist: "A", Track: "T1", Album: "Al", Timestamp: started, Duration: 200},
+		{Artist: "B", Track: "T2", Timestamp: started + 200, Duration: 180},
+	})
+	expected := map[string]interface{}{
+		"artist":    []string{"A", "B"},
+		"track":     []string{"T1", "T2"},
+		"album":     []string{"Al", ""},
+		"timestamp": []string{"1704164645", "1704164845"},
+		"duration":  []string{"200", "180"},
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("args = %+v, expected %+v", args, expected)
+	}
+}
+
+func TestScrobblable(t *testing.T) {
+	cases := []struct {
+		duration, listened time.Duration
+		scrobblable        bool
+	}{
+		{duration: 20 * time.Second, listened: 20 * time.Second, scrobblable: false},
+		{duration: 30 * time.Second, listened: 30 * time.Second, scrobblable: false},
+		{duration: 3 * time.Minute, listened: 89 * time.Second, scrobblable: false},
+		{duration: 3 * time.Minute, listened: 90 * time.Second, scrobblable: true},
+		{duration: 20 * time.Minute, listened: 4 * time.Minute, scrobblable: true},
+		{duration: 20 * time.Minute, listened: 3 * time.Minute, scrobblable: false},
+	}
+	for _, c := range cases {
+		if Scrobblable(c.duration, c.listened) != c.scrobblable {
+			t.Errorf("duration %s, listened %s: scrobblable = %v, expected %v", c.duration, c.listened, !c.scrobblable, c.scrobblable)
+		}
+	}
+}
+
+func TestReportQueuesWhenAuthed(t *testing.T) {
+	c := &Client{}
+	c.queue = newScrobbleQueue(c)
+	before := c.queue.Pending()
+
+	song := spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{
+		Name:     "T",
+		Artists:  []spotify.SimpleArtist{{Name: "A"}},
+		Duration: int(3 * time.Minute / time.Millisecond),
+	}}
+	Report(c, ReportPhaseComplete, song, 2*time.Minute, time.Now())
+	if pending := c.queue.Pending(); pending != before {
+		t.Fatalf("pending = %d, expected nothing queued without Last.fm configured", pending)
+	}
+	c.api = lastfmgo.New("key", "secret")
+	Report(c, ReportPhaseComplete, song, 2*time.Minute, time.Now())
+	if pending := c.queue.Pending(); pending != before {
+		t.Fatalf("pending = %d, expected nothing queued without session", pending)
+	}
+
+	c.SetSession("session")
+	Report(c, ReportPhaseComplete, song, time.Minute, time.Now())
+	if pending := c.queue.Pending(); pending != before {
+		t.Fatalf("pending = %d, expected not scrobblable track skipped", pending)
+	}
+	Report(c, ReportPhaseComplete, song, 2*time.Minute, time.Now())
+	if pending := c.queue.Pending(); pending != before+1 {
+		t.Fatalf("pending = %d, expected %d", pending, before+1)
+	}
+
+	// kept for retry until the session is available
+	c.SetSession("")
+	if _, err := c.queue.Flush(); err == nil {
+		t.Fatal("expected flush failed without session")
+	}
+	if pending := c.queue.Pending(); pending != before+1 {
+		t.Errorf("pending = %d after failed flush, expected %d", pending, before+1)
+	}
+
+	// dropped with the auth
+	if err := c.queue.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if pending := c.queue.Pending(); pending != 0 {
+		t.Errorf("pending = %d after cleared, expected 0", pending)
+	}
+}
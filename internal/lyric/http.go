@@ -0,0 +1,141 @@
+package lyric
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LRCLIBURLTemplate the api of https://lrclib.net
+const LRCLIBURLTemplate = "https://lrclib.net/api/get?artist_name={artist}&track_name={title}&album_name={album}&duration={duration}"
+
+// maxLyricsSize the response larger than it is rejected
+const maxLyricsSize = 1 << 20
+
+// HTTPProvider fetches lyrics from the url built by the template, the placeholders
+// {id}, {isrc}, {artist}, {artists}, {title}, {album} and {duration} (in seconds) are replaced with escaped values.
+// The response can be lrc or plain text, or json like LRCLIB:
+// {"syncedLyrics": "[00:01.00]...", "plainLyrics": "...", "instrumental": false}, or an array of it.
+// The translation in lrc can be returned by the additional field "translatedLyrics"
+type HTTPProvider struct {
+	ProviderName string
+	URLTemplate  string
+	Client       *http.Client
+}
+
+func NewHTTPProvider(name, urlTemplate string, client *http.Client) *HTTPProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPProvider{ProviderName: name, URLTemplate: urlTemplate, Client: client}
+}
+
+func (p *HTTPProvider) Name() string {
+	return p.ProviderName
+}
+
+func (p *HTTPProvider) buildURL(track Track) string {
+	var artist string
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+	return strings.NewReplacer(
+		"{id}", url.QueryEscape(track.Id),
+		"{isrc}", url.QueryEscape(track.ISRC),
+		"{artist}", url.QueryEscape(artist),
+		"{artists}", url.QueryEscape(strings.Join(track.Artists, ", ")),
+		"{title}", url.QueryEscape(track.Title),
+		"{album}", url.QueryEscape(track.Album),
+		"{duration}", strconv.Itoa(int(track.Duration.Seconds())),
+	).Replace(p.URLTemplate)
+}
+
+func (p *HTTPProvider) Lyrics(ctx context.Context, track Track) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.buildURL(track), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "build lyrics request failed")
+	}
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "request lyrics failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("request lyrics failed, status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxLyricsSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "read lyrics failed")
+	}
+	return p.parse(body)
+}
+
+// lrclibLyrics the lyrics in json of LRCLIB
+type lrclibLyrics struct {
+	Instrumental bool   `json:"instrumental"`
+	PlainLyrics  string `json:"plainLyrics"`
+	SyncedLyrics string `json:"syncedLyrics"`
+	// TranslatedLyrics not provided by LRCLIB, for the compatible apis
+	TranslatedLyrics string `json:"translatedLyrics"`
+}
+
+func (p *HTTPProvider) parse(body []byte) (*Result, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, ErrNotFound
+	}
+
+	switch body[0] {
+	case '[':
+		if isSyncedLRC(string(body)) {
+			break
+		}
+		var list []lrclibLyrics
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, errors.Wrap(err, "decode lyrics failed")
+		}
+		return p.fromJSON(list...)
+	case '{':
+		var l lrclibLyrics
+		if err := json.Unmarshal(body, &l); err != nil {
+			return nil, errors.Wrap(err, "decode lyrics failed")
+		}
+		return p.fromJSON(l)
+	}
+
+	text := string(body)
+	return &Result{Provider: p.Name(), Synced: isSyncedLRC(text), Lyrics: text}, nil
+}
+
+// fromJSON prefers the synced lyrics in the list
+func (p *HTTPProvider) fromJSON(list ...lrclibLyrics) (*Result, error) {
+	var plain *Result
+	for _, l := range list {
+		if l.Instrumental {
+			continue
+		}
+		if strings.TrimSpace(l.SyncedLyrics) != "" {
+			return &Result{Provider: p.Name(), Synced: true, Lyrics: l.SyncedLyrics, Translation: l.TranslatedLyrics}, nil
+		}
+		if plain == nil && strings.TrimSpace(l.PlainLyrics) != "" {
+			plain = &Result{Provider: p.Name(), Lyrics: l.PlainLyrics}
+		}
+	}
+	if plain == nil {
+		return nil, ErrNotFound
+	}
+	return plain, nil
+}
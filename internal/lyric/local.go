@@ -0,0 +1,114 @@
+package lyric
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// fileNameReplacer replaces the chars which can't be used in file name
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// localCandidates names of lrc file without ext, in order of priority
+func localCandidates(track Track) []string {
+	var names []string
+	if track.Id != "" {
+		names = append(names, track.Id)
+	}
+	if track.Title != "" && len(track.Artists) > 0 {
+		names = append(names, track.Artists[0]+" - "+track.Title)
+		if len(track.Artists) > 1 {
+			names = append(names, strings.Join(track.Artists, ", ")+" - "+track.Title)
+		}
+	}
+	if track.ISRC != "" {
+		names = append(names, track.ISRC)
+	}
+	for i := range names {
+		names[i] = strings.ToLower(fileNameReplacer.Replace(strings.TrimSpace(names[i])))
+	}
+	return names
+}
+
+// transSuffix the translation of {name}.lrc is in {name}.trans.lrc
+const transSuffix = ".trans"
+
+// FindLocalLRC looks for the lrc file in dir matched by track id, "artist - title" and ISRC in order,
+// the names are matched case-insensitively. The file whose [ar:] or [ti:] tag doesn't match the track is skipped.
+// transPath is empty if the translation isn't found
+func FindLocalLRC(dir string, track Track) (filePath, transPath string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	files := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".lrc") {
+			continue
+		}
+		files[strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))] = name
+	}
+
+	for _, candidate := range localCandidates(track) {
+		if name, ok := files[candidate]; ok {
+			if !localLRCBelongsTo(filepath.Join(dir, name), track) {
+				continue
+			}
+			if transName, ok := files[candidate+transSuffix]; ok {
+				transPath = filepath.Join(dir, transName)
+			}
+			return filepath.Join(dir, name), transPath, nil
+		}
+	}
+	return "", "", os.ErrNotExist
+}
+
+// localLRCBelongsTo checks the metadata of lrc file, the file can't be read is checked by the caller
+func localLRCBelongsTo(filePath string, track Track) bool {
+	lrcFile, err := OpenLRCFile(filePath)
+	if err != nil {
+		return true
+	}
+	return lrcFile.Metadata().BelongsTo(track)
+}
+
+// LocalProvider reads the sidecar lrc files in Dir, with the translation in {name}.trans.lrc
+type LocalProvider struct {
+	Dir string
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+func (p *LocalProvider) Lyrics(_ context.Context, track Track) (*Result, error) {
+	filePath, transPath, err := FindLocalLRC(p.Dir, track)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "find local lrc failed")
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "read local lrc failed")
+	}
+	text := string(content)
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrNotFound
+	}
+	res := &Result{Provider: p.Name(), Synced: isSyncedLRC(text), Lyrics: text}
+	if transPath != "" {
+		trans, err := os.ReadFile(transPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "read local translation failed")
+		}
+		res.Translation = string(trans)
+	}
+	return res, nil
+}
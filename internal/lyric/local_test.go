@@ -0,0 +1,52 @@
+package lyric
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalProvider(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("AC_DC - Thunderstruck.LRC", "[00:01.00]by name")
+	write("USAT29900609.lrc", "[00:01.00]by isrc")
+
+	var (
+		provider = &LocalProvider{Dir: dir}
+		track    = Track{Id: "57bgtoPSgt236HzfBOd8kj", ISRC: "USAT29900609", Artists: []string{"AC/DC"}, Title: "Thunderstruck"}
+	)
+	res, err := provider.Lyrics(context.Background(), track)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Synced || res.Lyrics != "[00:01.00]by name" {
+		t.Errorf("result = %+v, expected to match by name first", res)
+	}
+
+	write("57bgtoPSgt236HzfBOd8kj.lrc", "[00:01.00]by id")
+	if res, _ = provider.Lyrics(context.Background(), track); res.Lyrics != "[00:01.00]by id" {
+		t.Errorf("result = %+v, expected to match by track id first", res)
+	}
+
+	write("57bgtoPSgt236HzfBOd8kj.Trans.lrc", "[00:01.00]translation")
+	if res, _ = provider.Lyrics(context.Background(), track); res.Translation != "[00:01.00]translation" {
+		t.Errorf("result = %+v, expected the translation read", res)
+	}
+
+	// the file of another track is skipped by the tags
+	write("57bgtoPSgt236HzfBOd8kj.lrc", "[ti:Another Song]\n[00:01.00]by id")
+	if res, _ = provider.Lyrics(context.Background(), track); res.Lyrics != "[00:01.00]by name" {
+		t.Errorf("result = %+v, expected the mismatched file skipped", res)
+	}
+
+	if _, err = provider.Lyrics(context.Background(), Track{Id: "unknown"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, expected not found", err)
+	}
+}
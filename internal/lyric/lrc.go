@@ -18,9 +18,21 @@ import (
 // https://en.wikipedia.org/wiki/LRC_(file_format)
 
 type LRCFile struct {
+	metadata  LRCMetadata
 	fragments []LRCFragment
 }
 
+// LRCMetadata the ID tags of lrc, e.g. [ar:Artist]
+type LRCMetadata struct {
+	Artist string
+	Title  string
+	Album  string
+	Length time.Duration
+	// Offset in ms, which has been applied to the fragments.
+	// The positive offset shows the lyrics sooner
+	Offset int64
+}
+
 type TranslateLRCFile struct {
 	fragments map[int64]string
 }
@@ -32,6 +44,14 @@ func (tf *TranslateLRCFile) FindByTimeMs(timeMs int64) string {
 type LRCFragment struct {
 	StartTimeMs int64
 	Content     string
+	// Words of enhanced lrc, which are timed by <mm:ss.xx>
+	Words []LRCWord
+}
+
+// LRCWord the word of enhanced lrc, Content keeps the spaces around the word
+type LRCWord struct {
+	StartTimeMs int64
+	Content     string
 }
 
 func NewLRCFileFromFrags(frags []LRCFragment) *LRCFile {
@@ -40,6 +60,44 @@ func NewLRCFileFromFrags(frags []LRCFragment) *LRCFile {
 	}
 }
 
+func (f *LRCFile) Fragments() []LRCFragment {
+	return f.fragments
+}
+
+func (f *LRCFile) Metadata() LRCMetadata {
+	return f.metadata
+}
+
+// String formats the fragments in lrc, which can be read by ReadLRC.
+// The offset tag isn't written since it has been applied
+func (f *LRCFile) String() string {
+	var builder strings.Builder
+	for _, tag := range [][2]string{{"ar", f.metadata.Artist}, {"ti", f.metadata.Title}, {"al", f.metadata.Album}} {
+		if tag[1] != "" {
+			builder.WriteString("[" + tag[0] + ":" + tag[1] + "]\n")
+		}
+	}
+	if f.metadata.Length > 0 {
+		seconds := int(f.metadata.Length.Seconds())
+		builder.WriteString(fmt.Sprintf("[length:%02d:%02d]\n", seconds/60, seconds%60))
+	}
+	for _, frag := range f.fragments {
+		builder.WriteString("[" + formatLRCTime(frag.StartTimeMs) + "]")
+		if len(frag.Words) == 0 {
+			builder.WriteString(frag.Content)
+		}
+		for _, word := range frag.Words {
+			builder.WriteString("<" + formatLRCTime(word.StartTimeMs) + ">" + word.Content)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func formatLRCTime(timeMs int64) string {
+	return fmt.Sprintf("%02d:%06.3f", timeMs/60000, float64(timeMs%60000)/1000)
+}
+
 func OpenLRCFile(filePath string) (lrcFile *LRCFile, err error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -51,11 +109,19 @@ func OpenLRCFile(filePath string) (lrcFile *LRCFile, err error) {
 }
 
 func ReadLRC(reader io.Reader) (lrcFile *LRCFile, err error) {
-	var fragments []LRCFragment
+	var (
+		fragments []LRCFragment
+		metadata  LRCMetadata
+	)
 
 	lineNo := 1
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
+		if key, value, ok := parseLRCTag(scanner.Text()); ok {
+			metadata.set(key, value)
+			lineNo++
+			continue
+		}
 		var lineFragments []LRCFragment
 		lineFragments, err = readLRCLine(scanner.Text(), lineNo)
 		if err != nil {
@@ -64,17 +130,93 @@ func ReadLRC(reader io.Reader) (lrcFile *LRCFile, err error) {
 		fragments = append(fragments, lineFragments...)
 		lineNo++
 	}
+	// the malformed lines are skipped
+	err = nil
 
-	sort.Slice(fragments, func(i, j int) bool {
+	if metadata.Offset != 0 {
+		for i := range fragments {
+			fragments[i].StartTimeMs = max(fragments[i].StartTimeMs-metadata.Offset, 0)
+			for j := range fragments[i].Words {
+				fragments[i].Words[j].StartTimeMs = max(fragments[i].Words[j].StartTimeMs-metadata.Offset, 0)
+			}
+		}
+	}
+
+	// stable, the translation of bilingual lrc follows the original line
+	sort.SliceStable(fragments, func(i, j int) bool {
 		return fragments[i].StartTimeMs < fragments[j].StartTimeMs
 	})
 
 	lrcFile = &LRCFile{
+		metadata:  metadata,
 		fragments: fragments,
 	}
 	return
 }
 
+// parseLRCTag parses the ID tag line, e.g. [ar:Artist]
+func parseLRCTag(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(line[1:len(line)-1], ":")
+	if !ok || key == "" {
+		return "", "", false
+	}
+	for _, r := range key {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && r != '#' {
+			return "", "", false
+		}
+	}
+	return strings.ToLower(key), strings.TrimSpace(value), true
+}
+
+func (m *LRCMetadata) set(key, value string) {
+	switch key {
+	case "ar":
+		m.Artist = value
+	case "ti":
+		m.Title = value
+	case "al":
+		m.Album = value
+	case "length":
+		if tm, err := parseLRCTime("["+value+"]", "[", "]"); err == nil {
+			m.Length = tm
+		}
+	case "offset":
+		if offset, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64); err == nil {
+			m.Offset = offset
+		}
+	}
+}
+
+// BelongsTo whether the artist and title tags match the track, the missing tags are ignored
+func (m LRCMetadata) BelongsTo(track Track) bool {
+	if m.Title != "" && !looseEqual(m.Title, track.Title) {
+		return false
+	}
+	if m.Artist == "" || len(track.Artists) == 0 {
+		return true
+	}
+	for _, artist := range track.Artists {
+		if looseEqual(m.Artist, artist) {
+			return true
+		}
+	}
+	return false
+}
+
+// looseEqual whether one contains the other case-insensitively,
+// e.g. "Song" and "Song (Remastered)", "A" and "A, B"
+func looseEqual(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return a == b
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
 func ReadTranslateLRC(reader io.Reader) (f *TranslateLRCFile, err error) {
 	lrcFile, e := ReadLRC(reader)
 	if e != nil {
@@ -87,6 +229,35 @@ func ReadTranslateLRC(reader io.Reader) (f *TranslateLRCFile, err error) {
 	return
 }
 
+// SplitBilingualLRC splits the bilingual lrc, in which the translation shares the timestamp
+// with the original line and follows it
+func SplitBilingualLRC(file *LRCFile) (*LRCFile, *TranslateLRCFile) {
+	var (
+		fragments = make([]LRCFragment, 0, len(file.fragments))
+		trans     = &TranslateLRCFile{fragments: map[int64]string{}}
+	)
+	for _, fragment := range file.fragments {
+		last := len(fragments) - 1
+		if last >= 0 && fragments[last].StartTimeMs == fragment.StartTimeMs && fragment.Content != "" {
+			if _, ok := trans.fragments[fragment.StartTimeMs]; !ok {
+				trans.fragments[fragment.StartTimeMs] = fragment.Content
+			}
+			continue
+		}
+		fragments = append(fragments, fragment)
+	}
+	return &LRCFile{fragments: fragments}, trans
+}
+
+// Merge fills the lines missing in tf from other
+func (tf *TranslateLRCFile) Merge(other *TranslateLRCFile) {
+	for timeMs, content := range other.fragments {
+		if tf.fragments[timeMs] == "" {
+			tf.fragments[timeMs] = content
+		}
+	}
+}
+
 func readLRCLine(line string, lineNo int) (fragments []LRCFragment, err error) {
 	line = strings.TrimSpace(line)
 	if line == "" {
@@ -160,6 +331,7 @@ func parseLRCTime(line, openChar, closeChar string) (tm time.Duration, err error
 	return
 }
 
+// parseContentLine parses the words timed by <mm:ss.xx> of enhanced lrc, the words are kept in one fragment
 func parseContentLine(line string, tm time.Duration) (fragments []LRCFragment, err error) {
 	if !strings.Contains(line, "<") {
 		fragments = append(fragments, LRCFragment{
@@ -169,9 +341,17 @@ func parseContentLine(line string, tm time.Duration) (fragments []LRCFragment, e
 		return
 	}
 
-	previousTm := tm
-	startIndex := 0
-	lastIndex := 0
+	var (
+		words      []LRCWord
+		previousTm = tm
+		startIndex = 0
+		lastIndex  = 0
+	)
+	addWord := func(content string) {
+		if strings.TrimSpace(content) != "" {
+			words = append(words, LRCWord{StartTimeMs: previousTm.Milliseconds(), Content: content})
+		}
+	}
 	for {
 		idx := strings.Index(line[lastIndex:], "<")
 		closeIndex := strings.Index(line[lastIndex:], ">")
@@ -183,19 +363,22 @@ func parseContentLine(line string, tm time.Duration) (fragments []LRCFragment, e
 
 		splitTm, tmErr := parseLRCTime(line[idx:], "<", ">")
 		if tmErr == nil {
-			fragments = append(fragments, LRCFragment{
-				StartTimeMs: previousTm.Milliseconds(),
-				Content:     strings.TrimSpace(line[startIndex:idx]),
-			})
+			addWord(line[startIndex:idx])
 			startIndex = closeIndex + 1
 			previousTm = splitTm
 		}
 		lastIndex = closeIndex + 1
 	}
+	addWord(line[startIndex:])
 
+	var content strings.Builder
+	for _, word := range words {
+		content.WriteString(word.Content)
+	}
 	fragments = append(fragments, LRCFragment{
-		StartTimeMs: previousTm.Milliseconds(),
-		Content:     strings.TrimSpace(line[startIndex:]),
+		StartTimeMs: tm.Milliseconds(),
+		Content:     strings.TrimSpace(content.String()),
+		Words:       words,
 	})
 	return
 }
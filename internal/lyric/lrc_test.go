@@ -0,0 +1,111 @@
+package lyric
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLRCMetadata(t *testing.T) {
+	l, err := ReadLRC(strings.NewReader("[ar: Cutting Crew ]\n[ti:(I Just) Died In Your Arms]\n[al:Broadcast]\n[length:04:31]\n[by:someone]\n[offset:+500]\n[00:01.00]first\n[00:02.00]<00:02.00>second <00:02.80>line"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := LRCMetadata{Artist: "Cutting Crew", Title: "(I Just) Died In Your Arms", Album: "Broadcast", Length: 271 * time.Second, Offset: 500}
+	if l.Metadata() != expected {
+		t.Errorf("metadata = %+v, expected %+v", l.Metadata(), expected)
+	}
+
+	frags := l.Fragments()
+	if len(frags) != 2 || frags[0].StartTimeMs != 500 || frags[1].StartTimeMs != 1500 || frags[1].Words[1].StartTimeMs != 2300 {
+		t.Errorf("fragments = %+v, expected the offset applied", frags)
+	}
+
+	again, _ := ReadLRC(strings.NewReader(l.String()))
+	if again.Metadata().Offset != 0 || again.Metadata().Title != expected.Title || again.Fragments()[0].StartTimeMs != 500 {
+		t.Errorf("metadata = %+v, fragments = %+v", again.Metadata(), again.Fragments())
+	}
+
+	l, _ = ReadLRC(strings.NewReader("[offset:-1500]\n[00:01.00]first"))
+	if l.Fragments()[0].StartTimeMs != 2500 {
+		t.Errorf("fragments = %+v, expected shown later", l.Fragments())
+	}
+}
+
+func TestReadLRCMalformed(t *testing.T) {
+	l, err := ReadLRC(strings.NewReader("no timestamp\n[xx:yy]bad time\n[00:03.00]third\n\n[00:01.00][00:05.00]twice\n[00:02.00]second\n[00:04.00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		frags    = l.Fragments()
+		expected = []LRCFragment{{StartTimeMs: 1000, Content: "twice"}, {StartTimeMs: 2000, Content: "second"}, {StartTimeMs: 3000, Content: "third"}, {StartTimeMs: 5000, Content: "twice"}}
+	)
+	if len(frags) != len(expected) {
+		t.Fatalf("fragments = %+v, expected %+v", frags, expected)
+	}
+	for i := range expected {
+		if frags[i].StartTimeMs != expected[i].StartTimeMs || frags[i].Content != expected[i].Content {
+			t.Errorf("fragment %d = %+v, expected %+v", i, frags[i], expected[i])
+		}
+	}
+}
+
+func TestLRCMetadataBelongsTo(t *testing.T) {
+	track := Track{Artists: []string{"Cutting Crew"}, Title: "(I Just) Died In Your Arms"}
+	cases := []struct {
+		metadata LRCMetadata
+		belongs  bool
+	}{
+		{metadata: LRCMetadata{}, belongs: true},
+		{metadata: LRCMetadata{Artist: "cutting crew", Title: "(I Just) Died In Your Arms - Remastered"}, belongs: true},
+		{metadata: LRCMetadata{Artist: "Cutting Crew, Someone"}, belongs: true},
+		{metadata: LRCMetadata{Title: "Another Song"}, belongs: false},
+		{metadata: LRCMetadata{Artist: "Another Artist", Title: "(I Just) Died In Your Arms"}, belongs: false},
+	}
+	for _, c := range cases {
+		if c.metadata.BelongsTo(track) != c.belongs {
+			t.Errorf("%+v belongs = %v, expected %v", c.metadata, !c.belongs, c.belongs)
+		}
+	}
+}
+
+func TestLRCFileString(t *testing.T) {
+	l, err := ReadLRC(strings.NewReader("[00:01.50]first\n[01:02.345]second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadLRC(strings.NewReader(l.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Fragments()) != 2 || again.Fragments()[1].StartTimeMs != 62345 || again.Fragments()[1].Content != "second" {
+		t.Errorf("fragments = %+v", again.Fragments())
+	}
+}
+
+func TestEnhancedLRCWords(t *testing.T) {
+	l, err := ReadLRC(strings.NewReader("[00:01.00]<00:01.00>Hello <00:01.50>world\n[00:03.00]plain"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frags := l.Fragments()
+	if len(frags) != 2 {
+		t.Fatalf("fragments = %+v, expected words grouped into their line", frags)
+	}
+	if frags[0].Content != "Hello world" || len(frags[0].Words) != 2 || frags[0].Words[1].StartTimeMs != 1500 {
+		t.Errorf("fragment = %+v", frags[0])
+	}
+	if len(frags[1].Words) != 0 {
+		t.Errorf("fragment = %+v, expected no words", frags[1])
+	}
+
+	again, err := ReadLRC(strings.NewReader(l.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := again.Fragments()[0].Words; len(w) != 2 || w[0].Content != "Hello " || w[1].Content != "world" {
+		t.Errorf("words = %+v", w)
+	}
+}
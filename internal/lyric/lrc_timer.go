@@ -15,6 +15,7 @@ type LRCTimer struct {
 	listeners []Listener
 
 	curIndex int
+	stopped  bool
 	l        sync.Mutex
 }
 
@@ -27,6 +28,8 @@ func NewLRCTimer(file *LRCFile, transFile *TranslateLRCFile) *LRCTimer {
 }
 
 func (t *LRCTimer) Timer() chan<- time.Duration {
+	t.l.Lock()
+	defer t.l.Unlock()
 	return t.timer
 }
 
@@ -34,6 +37,8 @@ func (t *LRCTimer) AddListener(l Listener) {
 	t.listeners = append(t.listeners, l)
 }
 
+// Start calls the listeners with the fragment being played until Stop, which may be called before Start.
+// The listeners are called on the goroutine of Start
 func (t *LRCTimer) Start() {
 	fragments := t.file.fragments
 
@@ -41,28 +46,36 @@ func (t *LRCTimer) Start() {
 		return
 	}
 
-	t.Rewind()
+	t.l.Lock()
+	if t.stopped {
+		t.l.Unlock()
+		return
+	}
+	t.curIndex = 0
 	t.stop = make(chan struct{})
+	stop, timer, listeners := t.stop, t.timer, t.listeners
+	t.l.Unlock()
+
 	var (
 		current      = fragments[0]
 		transContent = t.transFile.FindByTimeMs(current.StartTimeMs)
 		isLast       = t.curIndex >= len(fragments)-1
 	)
-	for _, l := range t.listeners {
+	for _, l := range listeners {
 		l(current.StartTimeMs, current.Content, transContent, isLast, t.curIndex)
 	}
 	for {
 		select {
-		case <-t.stop:
+		case <-stop:
 			return
-		case duration := <-t.timer:
+		case duration := <-timer:
 			if isLast {
 				break
 			}
 
 			if duration < time.Duration(fragments[t.curIndex].StartTimeMs)*time.Millisecond {
-				for _, l := range t.listeners {
-					go l(current.StartTimeMs, current.Content, transContent, isLast, t.curIndex)
+				for _, l := range listeners {
+					l(current.StartTimeMs, current.Content, transContent, isLast, t.curIndex)
 				}
 				continue
 			}
@@ -77,20 +90,23 @@ func (t *LRCTimer) Start() {
 				isLast = t.curIndex > len(fragments)-1
 			}
 
-			for _, l := range t.listeners {
-				go l(current.StartTimeMs, current.Content, transContent, isLast, t.curIndex)
+			for _, l := range listeners {
+				l(current.StartTimeMs, current.Content, transContent, isLast, t.curIndex)
 			}
 		}
 	}
 }
 
 func (t *LRCTimer) IsStarted() bool {
+	t.l.Lock()
+	defer t.l.Unlock()
 	return t.timer != nil
 }
 
 func (t *LRCTimer) Stop() {
 	t.l.Lock()
 	defer t.l.Unlock()
+	t.stopped = true
 	if t.stop != nil {
 		close(t.stop)
 		t.stop = nil
@@ -118,3 +134,18 @@ func (t *LRCTimer) GetLRCFragment(index int) (*LRCFragment, *LRCFragment) {
 func (t *LRCTimer) IsEmpty() bool {
 	return nil == t.file || len(t.file.fragments) == 0
 }
+
+// CurIndex index of the fragment being played
+func (t *LRCTimer) CurIndex() int {
+	t.l.Lock()
+	defer t.l.Unlock()
+	return t.curIndex
+}
+
+// Len count of the fragments
+func (t *LRCTimer) Len() int {
+	if nil == t.file {
+		return 0
+	}
+	return len(t.file.fragments)
+}
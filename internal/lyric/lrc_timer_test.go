@@ -0,0 +1,44 @@
+package lyric
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRCTimerStop(t *testing.T) {
+	l, _ := ReadLRC(strings.NewReader("[00:01.00]first\n[00:02.00]second"))
+	tl, _ := ReadTranslateLRC(strings.NewReader("[00:00.00]"))
+
+	timer := NewLRCTimer(l, tl)
+	var calls int
+	timer.AddListener(func(int64, string, string, bool, int) { calls++ })
+	timer.Stop()
+	timer.Start() // returns at once if stopped before
+	if calls != 0 {
+		t.Errorf("calls = %d, expected no listener called after stop", calls)
+	}
+
+	timer = NewLRCTimer(l, tl)
+	var indexes []int
+	timer.AddListener(func(_ int64, _ string, _ string, _ bool, index int) { indexes = append(indexes, index) })
+	timer.AddListener(func(_ int64, _ string, _ string, _ bool, index int) {
+		if index == 1 {
+			timer.Stop()
+		}
+	})
+	done := make(chan struct{})
+	go func() {
+		timer.Start()
+		close(done)
+	}()
+	timer.Timer() <- 2500 * time.Millisecond
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timer isn't stopped")
+	}
+	if len(indexes) != 2 || indexes[1] != 1 {
+		t.Errorf("indexes = %v, expected [0 1]", indexes)
+	}
+}
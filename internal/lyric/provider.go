@@ -0,0 +1,141 @@
+package lyric
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("lyrics not found")
+
+// Track the track whose lyrics are looked for
+type Track struct {
+	Id       string
+	ISRC     string
+	Artists  []string
+	Title    string
+	Album    string
+	Duration time.Duration
+}
+
+// Result lyrics found by provider
+type Result struct {
+	Provider string
+	// Synced whether Lyrics is in lrc, otherwise it's plain text
+	Synced bool
+	Lyrics string
+	// Translation translated lyrics in lrc, optional
+	Translation string
+}
+
+// File parses the lyrics, the lines of plain text are spread across the duration of track.
+// The translation is read from Translation and the bilingual lines of Lyrics
+func (r *Result) File(duration time.Duration) (*LRCFile, *TranslateLRCFile, error) {
+	if !r.Synced {
+		return NewPlainLRCFile(r.Lyrics, duration), &TranslateLRCFile{}, nil
+	}
+
+	file, err := ReadLRC(strings.NewReader(r.Lyrics))
+	if err != nil {
+		return nil, nil, err
+	}
+	file, trans := SplitBilingualLRC(file)
+	if strings.TrimSpace(r.Translation) == "" {
+		return file, trans, nil
+	}
+	translated, err := ReadTranslateLRC(strings.NewReader(r.Translation))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "read translation failed")
+	}
+	translated.Merge(trans)
+	return file, translated, nil
+}
+
+// LyricsProvider finds lyrics of track, ErrNotFound is returned if the track has no lyrics
+type LyricsProvider interface {
+	Name() string
+	Lyrics(ctx context.Context, track Track) (*Result, error)
+}
+
+// Chain asks the providers in order, the first synced result wins, the plain one is used if no synced.
+// ErrNotFound is returned only if every provider has not found, so that it's safe to be cached
+type Chain []LyricsProvider
+
+func (c Chain) Name() string {
+	var names []string
+	for _, p := range c {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (c Chain) Lyrics(ctx context.Context, track Track) (*Result, error) {
+	var (
+		plain   *Result
+		lastErr error
+	)
+	for _, p := range c {
+		res, err := p.Lyrics(ctx, track)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				lastErr = errors.WithMessagef(err, "provider %s", p.Name())
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if res.Synced {
+			return res, nil
+		}
+		if plain == nil {
+			plain = res
+		}
+	}
+
+	if plain != nil {
+		return plain, nil
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, ErrNotFound
+}
+
+// plainLineDuration duration of each line of plain lyrics if the duration of track is unknown
+const plainLineDuration = 4 * time.Second
+
+// NewPlainLRCFile spreads the lines of plain lyrics across the duration
+func NewPlainLRCFile(text string, duration time.Duration) *LRCFile {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return NewLRCFileFromFrags(nil)
+	}
+
+	step := plainLineDuration
+	if duration > 0 {
+		step = duration / time.Duration(len(lines))
+	}
+	frags := make([]LRCFragment, 0, len(lines))
+	for i, line := range lines {
+		frags = append(frags, LRCFragment{StartTimeMs: (step * time.Duration(i)).Milliseconds(), Content: line})
+	}
+	return NewLRCFileFromFrags(frags)
+}
+
+// isSyncedLRC whether the text has timestamps of lrc
+func isSyncedLRC(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if _, err := parseLRCTime(strings.TrimSpace(line), "[", "]"); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package lyric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var testTrack = Track{Id: "11dFghVXANMlKmJXsNCbNl", Artists: []string{"Cutting Crew"}, Title: "(I Just) Died In Your Arms", Album: "Broadcast", Duration: 271000000000}
+
+func TestHTTPProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("track_name") != testTrack.Title || query.Get("duration") != "271" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/json":
+			_, _ = fmt.Fprint(w, `{"instrumental":false,"plainLyrics":"plain","syncedLyrics":"[00:01.00]synced"}`)
+		case "/list":
+			_, _ = fmt.Fprint(w, `[{"plainLyrics":"first plain","syncedLyrics":null},{"plainLyrics":"plain","syncedLyrics":"[00:01.00]synced"}]`)
+		case "/plain":
+			_, _ = fmt.Fprint(w, "line 1\nline 2")
+		case "/lrc":
+			_, _ = fmt.Fprint(w, "[ar:Cutting Crew]\n[00:01.00]synced")
+		case "/translated":
+			_, _ = fmt.Fprint(w, `{"syncedLyrics":"[00:01.00]synced","translatedLyrics":"[00:01.00]translated"}`)
+		case "/instrumental":
+			_, _ = fmt.Fprint(w, `{"instrumental":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		path        string
+		synced      bool
+		lyrics      string
+		translation string
+		err         error
+	}{
+		{path: "/json", synced: true, lyrics: "[00:01.00]synced"},
+		{path: "/list", synced: true, lyrics: "[00:01.00]synced"},
+		{path: "/plain", synced: false, lyrics: "line 1\nline 2"},
+		{path: "/lrc", synced: true, lyrics: "[ar:Cutting Crew]\n[00:01.00]synced"},
+		{path: "/translated", synced: true, lyrics: "[00:01.00]synced", translation: "[00:01.00]translated"},
+		{path: "/instrumental", err: ErrNotFound},
+		{path: "/missing", err: ErrNotFound},
+	}
+	for _, c := range cases {
+		provider := NewHTTPProvider("http", srv.URL+c.path+"?artist_name={artist}&track_name={title}&duration={duration}", srv.Client())
+		res, err := provider.Lyrics(context.Background(), testTrack)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("%s: err = %v, expected %v", c.path, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.path, err)
+			continue
+		}
+		if res.Synced != c.synced || res.Lyrics != c.lyrics || res.Translation != c.translation {
+			t.Errorf("%s: result = %+v", c.path, res)
+		}
+	}
+}
+
+// rewriteTransport sends the requests to the test server
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSpotifyProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/get_access_token":
+			_, _ = fmt.Fprint(w, `{"accessToken":"token","accessTokenExpirationTimestampMs":9999999999999,"isAnonymous":false}`)
+		case "/color-lyrics/v2/track/" + testTrack.Id:
+			_, _ = fmt.Fprint(w, `{"lyrics":{"syncType":"LINE_SYNCED","lines":[{"startTimeMs":"1000","words":"first"},{"startTimeMs":"62345","words":"second"}]}}`)
+		default:
+			// spotify responds empty body if the track has no lyrics
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+	provider := NewSpotifyProvider("sp_dc=cookie")
+	provider.Client.Client = &http.Client{Transport: rewriteTransport{target: target}}
+
+	res, err := provider.Lyrics(context.Background(), testTrack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Synced || res.Lyrics != "[00:01.000]first\n[01:02.345]second\n" {
+		t.Errorf("result = %+v", res)
+	}
+
+	if _, err = provider.Lyrics(context.Background(), Track{Id: "unknown"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, expected not found", err)
+	}
+}
+
+func TestSpotifyProviderCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// hang until the request is aborted
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+	provider := NewSpotifyProvider("sp_dc=cookie")
+	provider.Client.Client = &http.Client{Transport: rewriteTransport{target: target}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := provider.Lyrics(ctx, testTrack); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, expected deadline exceeded", err)
+	}
+}
+
+type fakeProvider struct {
+	name string
+	res  *Result
+	err  error
+}
+
+func (p fakeProvider) Name() string {
+	return p.name
+}
+
+func (p fakeProvider) Lyrics(_ context.Context, _ Track) (*Result, error) {
+	return p.res, p.err
+}
+
+func TestChain(t *testing.T) {
+	var (
+		plain    = fakeProvider{name: "plain", res: &Result{Provider: "plain", Lyrics: "plain"}}
+		synced   = fakeProvider{name: "synced", res: &Result{Provider: "synced", Synced: true, Lyrics: "[00:01.00]synced"}}
+		notFound = fakeProvider{name: "not_found", err: ErrNotFound}
+		failed   = fakeProvider{name: "failed", err: errors.New("network is down")}
+	)
+
+	if res, _ := (Chain{notFound, plain, synced}).Lyrics(context.Background(), testTrack); res.Provider != "synced" {
+		t.Errorf("provider = %s, expected the synced one", res.Provider)
+	}
+	if res, _ := (Chain{plain, failed}).Lyrics(context.Background(), testTrack); res.Provider != "plain" {
+		t.Errorf("provider = %s, expected the plain one", res.Provider)
+	}
+	if _, err := (Chain{notFound, failed}).Lyrics(context.Background(), testTrack); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, expected the failure rather than not found", err)
+	}
+	if _, err := (Chain{notFound}).Lyrics(context.Background(), testTrack); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, expected not found", err)
+	}
+
+	l := NewPlainLRCFile("a\n\nb\nc\n", 3000000000)
+	if frags := l.Fragments(); len(frags) != 3 || frags[2].StartTimeMs != 2000 {
+		t.Errorf("plain fragments = %+v", frags)
+	}
+}
+
+func TestResultTranslation(t *testing.T) {
+	res := &Result{Synced: true, Lyrics: "[00:02.00]second\n[00:02.00]第二行\n[00:01.00]first\n[00:01.00]第一行"}
+	file, trans, err := res.File(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frags := file.Fragments(); len(frags) != 2 || frags[0].Content != "first" || frags[1].Content != "second" {
+		t.Errorf("fragments = %+v, expected the bilingual lines removed", frags)
+	}
+	if trans.FindByTimeMs(1000) != "第一行" || trans.FindByTimeMs(2000) != "第二行" {
+		t.Errorf("translation = %+v", trans)
+	}
+
+	res.Translation = "[00:01.00]翻译"
+	if _, trans, _ = res.File(0); trans.FindByTimeMs(1000) != "翻译" || trans.FindByTimeMs(2000) != "第二行" {
+		t.Errorf("translation = %+v, expected merged with the provided one first", trans)
+	}
+}
@@ -0,0 +1,87 @@
+package lyric
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	lyricsapi "github.com/raitonoberu/lyricsapi/lyrics"
+)
+
+// spotifyUnsynced sync type of the lyrics without timestamps
+const spotifyUnsynced = "UNSYNCED"
+
+// SpotifyProvider fetches the lyrics shown in Spotify web player by user's sp_dc cookie
+type SpotifyProvider struct {
+	Client *lyricsapi.LyricsApi
+	// l serializes the requests, the http client of Client is replaced to carry the ctx of each call
+	l sync.Mutex
+}
+
+func NewSpotifyProvider(cookie string) *SpotifyProvider {
+	return &SpotifyProvider{Client: lyricsapi.NewLyricsApi(cookie)}
+}
+
+func (p *SpotifyProvider) Name() string {
+	return "spotify"
+}
+
+func (p *SpotifyProvider) Lyrics(ctx context.Context, track Track) (*Result, error) {
+	if track.Id == "" {
+		return nil, ErrNotFound
+	}
+	l, err := p.get(ctx, track.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get lyrics of spotify failed")
+	}
+	if l == nil || l.Lyrics == nil || len(l.Lyrics.Lines) == 0 {
+		return nil, ErrNotFound
+	}
+
+	res := &Result{Provider: p.Name(), Synced: l.Lyrics.SyncType != spotifyUnsynced}
+	if !res.Synced {
+		for _, line := range l.Lyrics.Lines {
+			res.Lyrics += line.Words + "\n"
+		}
+		return res, nil
+	}
+
+	var frags []LRCFragment
+	for _, line := range l.Lyrics.Lines {
+		frags = append(frags, LRCFragment{StartTimeMs: int64(line.Time), Content: line.Words})
+	}
+	res.Lyrics = NewLRCFileFromFrags(frags).String()
+	return res, nil
+}
+
+// get requests the lyrics with ctx, which isn't accepted by lyricsapi
+func (p *SpotifyProvider) get(ctx context.Context, id string) (*lyricsapi.LyricsResult, error) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	client := p.Client.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	withCtx := *client
+	withCtx.Transport = contextTransport{ctx: ctx, base: client.Transport}
+	p.Client.Client = &withCtx
+	defer func() { p.Client.Client = client }()
+
+	return p.Client.Get(id)
+}
+
+// contextTransport sends the requests with ctx, so that they're aborted when ctx is canceled
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req.WithContext(t.ctx))
+}
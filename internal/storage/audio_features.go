@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/zmb3/spotify/v2"
+)
+
+// AudioFeatures audio features of track, cached by track id
+type AudioFeatures struct {
+	TrackId spotify.ID
+}
+
+func (a AudioFeatures) GetDbName() string {
+	return types.AppDBName
+}
+
+func (a AudioFeatures) GetTableName() string {
+	return "audio_features"
+}
+
+func (a AudioFeatures) GetKey() string {
+	return string(a.TrackId)
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+// HttpCache responses of Web API, stored in a separate db to be cleared easily
+type HttpCache struct{}
+
+func (c HttpCache) GetDbName() string {
+	return types.AppCacheDBName
+}
+
+func (c HttpCache) GetTableName() string {
+	return "http_cache"
+}
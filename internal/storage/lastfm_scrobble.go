@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+)
+
+// PendingScrobble the scrobble hasn't been submitted to Last.fm, it's kept until submitted
+type PendingScrobble struct {
+	ID        uint64 `json:"id"`
+	Artist    string `json:"artist"`
+	Track     string `json:"track"`
+	Album     string `json:"album"`
+	Timestamp int64  `json:"timestamp"` // unix time when the track started playing
+	Duration  int    `json:"duration"`  // seconds
+}
+
+func (s *PendingScrobble) SetID(id uint64) {
+	s.ID = id
+}
+
+// PendingScrobbles the queue of scrobbles in order of adding, per profile
+type PendingScrobbles struct {
+	// Profile the queue of current profile if empty
+	Profile string
+}
+
+func (q PendingScrobbles) GetDbName() string {
+	return ProfileDBName(q.Profile)
+}
+
+func (q PendingScrobbles) GetTableName() string {
+	return "lastfm_scrobbles"
+}
+
+var errStopIterating = errors.New("stop iterating")
+
+// Add appends the scrobble to the queue
+func (q PendingScrobbles) Add(s *PendingScrobble) error {
+	_, err := NewTable().IncrAdd(q, s)
+	return err
+}
+
+// Head reads at most limit scrobbles from the head of queue
+func (q PendingScrobbles) Head(limit int) ([]PendingScrobble, error) {
+	var list []PendingScrobble
+	err := NewTable().AllMap(q, func(_, v []byte) error {
+		var s PendingScrobble
+		if err := json.Unmarshal(v, &s); err != nil {
+			utils.Logger().Printf("[WARN] decode pending scrobble failed: %+v", err)
+			return nil
+		}
+		list = append(list, s)
+		if len(list) >= limit {
+			return errStopIterating
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopIterating) && !errors.Is(err, ErrBucketNotExists) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Count the count of pending scrobbles
+func (q PendingScrobbles) Count() int {
+	var count int
+	_ = NewTable().AllMap(q, func(_, _ []byte) error {
+		count++
+		return nil
+	})
+	return count
+}
+
+// Clear deletes all the scrobbles of the queue
+func (q PendingScrobbles) Clear() error {
+	return NewTable().DeleteTable(q)
+}
+
+// Remove deletes the submitted scrobbles
+func (q PendingScrobbles) Remove(list []PendingScrobble) error {
+	table := NewTable()
+	for _, s := range list {
+		if err := table.DeleteByID(q, s.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package storage
+
+import "testing"
+
+func TestPendingScrobblesOfProfile(t *testing.T) {
+	queue := PendingScrobbles{Profile: "scrobbler"}
+	if err := queue.Add(&PendingScrobble{Artist: "A", Track: "T"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = queue.Clear() }()
+
+	// the queue bound to profile isn't changed by switching profile
+	SetCurProfile("another")
+	defer SetCurProfile(DefaultProfile)
+	list, err := queue.Head(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Track != "T" {
+		t.Fatalf("list = %+v, expected the scrobble of profile", list)
+	}
+	if count := (PendingScrobbles{}).Count(); count != 0 {
+		t.Errorf("count = %d, expected the queue of current profile empty", count)
+	}
+
+	if err = queue.Remove(list); err != nil {
+		t.Fatal(err)
+	}
+	if count := queue.Count(); count != 0 {
+		t.Errorf("count = %d after removed, expected 0", count)
+	}
+}
@@ -3,19 +3,19 @@ package storage
 import (
 	"encoding/json"
 
-	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils/secret"
 )
 
 type LastfmUser struct {
-	Id         string `json:"id"`
-	Name       string `json:"name"`
-	RealName   string `json:"real_name"`
-	Url        string `json:"url"`
-	SessionKey string `json:"session_key"`
+	Id         string        `json:"id"`
+	Name       string        `json:"name"`
+	RealName   string        `json:"real_name"`
+	Url        string        `json:"url"`
+	SessionKey secret.String `json:"session_key"`
 }
 
 func (u *LastfmUser) GetDbName() string {
-	return types.AppDBName
+	return ProfileDBName("")
 }
 
 func (u *LastfmUser) GetTableName() string {
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/zmb3/spotify/v2"
+)
+
+// LikedSongs local index of user's saved tracks
+type LikedSongs struct {
+	UserId string `json:"user_id"`
+	// LatestAddedAt added time of the newest saved track, incremental sync stops here
+	LatestAddedAt string       `json:"latest_added_at"`
+	Ids           []spotify.ID `json:"ids"`
+}
+
+func (l LikedSongs) GetDbName() string {
+	return ProfileDBName("")
+}
+
+func (l LikedSongs) GetTableName() string {
+	return "default_bucket"
+}
+
+func (l LikedSongs) GetKey() string {
+	return "liked_songs"
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/go-musicfox/spotifox/utils"
@@ -40,6 +41,7 @@ func NewLocalDB(dbName string) (*LocalDB, error) {
 var DBManager *LocalDBManager
 
 type LocalDBManager struct {
+	l        sync.Mutex
 	localDBs map[string]*LocalDB
 }
 
@@ -56,6 +58,8 @@ func (dm *LocalDBManager) GetDBFromCache(db interface{}) (localDB *LocalDB, err
 		return nil, errors.New("param(db) expect a string or db.Model")
 	}
 
+	dm.l.Lock()
+	defer dm.l.Unlock()
 	if dm.localDBs == nil {
 		dm.localDBs = map[string]*LocalDB{}
 	}
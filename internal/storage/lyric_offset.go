@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+// LyricOffset offset of lyrics adjusted for the track, which is added to main.lyricOffset.
+// It's shared by all profiles because it depends on the lyrics rather than the account
+type LyricOffset struct {
+	TrackId string
+}
+
+func (o LyricOffset) GetDbName() string {
+	return types.AppDBName
+}
+
+func (o LyricOffset) GetTableName() string {
+	return "lyric_offset"
+}
+
+func (o LyricOffset) GetKey() string {
+	return o.TrackId
+}
+
+// Load reads the offset, zero if not adjusted
+func (o LyricOffset) Load() time.Duration {
+	jsonStr, err := NewTable().GetByKVModel(o)
+	if err != nil || len(jsonStr) == 0 {
+		return 0
+	}
+	var ms int64
+	if err = json.Unmarshal(jsonStr, &ms); err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Adjust adds delta to offset within ±LyricOffsetMax, and saves it if the track is known
+func (o LyricOffset) Adjust(offset, delta time.Duration) time.Duration {
+	offset = min(max(offset+delta, -types.LyricOffsetMax), types.LyricOffsetMax)
+	if o.TrackId != "" {
+		o.Store(offset)
+	}
+	return offset
+}
+
+// Store saves the offset, the zero offset is deleted
+func (o LyricOffset) Store(offset time.Duration) {
+	if offset == 0 {
+		_ = NewTable().DeleteByKVModel(o)
+		return
+	}
+	_ = NewTable().SetByKVModel(o, offset.Milliseconds())
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+func TestMain(m *testing.M) {
+	// keep the db out of the real data dir
+	root, err := os.MkdirTemp("", "spotifox-storage")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("SPOTIFOX_ROOT", root)
+	DBManager = new(LocalDBManager)
+	code := m.Run()
+	_ = os.RemoveAll(root)
+	os.Exit(code)
+}
+
+func TestLyricOffsetStore(t *testing.T) {
+	o := LyricOffset{TrackId: "4uLU6hMCjMI75M1A2tKUQC"}
+	if offset := o.Load(); offset != 0 {
+		t.Errorf("offset = %s, expected 0 if not adjusted", offset)
+	}
+
+	o.Store(-300 * time.Millisecond)
+	if offset := o.Load(); offset != -300*time.Millisecond {
+		t.Errorf("offset = %s, expected -300ms", offset)
+	}
+	if offset := (LyricOffset{TrackId: "another"}).Load(); offset != 0 {
+		t.Errorf("offset = %s, expected offset of other tracks not changed", offset)
+	}
+
+	o.Store(0)
+	if jsonStr, _ := NewTable().GetByKVModel(o); len(jsonStr) != 0 {
+		t.Errorf("stored = %s, expected zero offset deleted", jsonStr)
+	}
+}
+
+func TestLyricOffsetAdjust(t *testing.T) {
+	cases := []struct {
+		offset, delta, expected time.Duration
+	}{
+		{offset: 0, delta: types.LyricOffsetStep, expected: 100 * time.Millisecond},
+		{offset: 100 * time.Millisecond, delta: -types.LyricOffsetStep, expected: 0},
+		{offset: 0, delta: -types.LyricOffsetStep, expected: -100 * time.Millisecond},
+		{offset: types.LyricOffsetMax, delta: types.LyricOffsetStep, expected: types.LyricOffsetMax},
+		{offset: -types.LyricOffsetMax, delta: -types.LyricOffsetStep, expected: -types.LyricOffsetMax},
+	}
+	o := LyricOffset{TrackId: "6rqhFgbbKwnb9MLmUQDhG6"}
+	for _, c := range cases {
+		if offset := o.Adjust(c.offset, c.delta); offset != c.expected {
+			t.Errorf("adjust %s by %s = %s, expected %s", c.offset, c.delta, offset, c.expected)
+		}
+		if offset := o.Load(); offset != c.expected {
+			t.Errorf("stored offset = %s, expected %s", offset, c.expected)
+		}
+	}
+
+	if offset := (LyricOffset{}).Adjust(0, types.LyricOffsetStep); offset != types.LyricOffsetStep {
+		t.Errorf("offset = %s, expected adjusted without a track", offset)
+	}
+	if offset := (LyricOffset{}).Load(); offset != 0 {
+		t.Errorf("offset = %s, expected not stored without a track", offset)
+	}
+}
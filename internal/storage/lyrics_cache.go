@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+// LyricsCache lyrics fetched by provider, cached by provider, source and track id.
+// NotFound is cached as well, so that the track without lyrics isn't fetched every time
+type LyricsCache struct {
+	Provider string `json:"-"`
+	// Source identity of the provider whose results vary with settings, e.g. url template of http provider
+	Source  string `json:"-"`
+	TrackId string `json:"-"`
+	Synced  bool   `json:"synced"`
+	Lyrics  string `json:"lyrics"`
+	// Translation translated lyrics in lrc
+	Translation string    `json:"translation,omitempty"`
+	NotFound    bool      `json:"not_found"`
+	CachedAt    time.Time `json:"cached_at"`
+}
+
+func (c LyricsCache) GetDbName() string {
+	return types.AppCacheDBName
+}
+
+func (c LyricsCache) GetTableName() string {
+	return "lyrics"
+}
+
+func (c LyricsCache) GetKey() string {
+	if c.Source == "" {
+		return c.Provider + ":" + c.TrackId
+	}
+	// the lyrics cached for the source replaced are not read
+	sum := sha256.Sum256([]byte(c.Source))
+	return c.Provider + "@" + hex.EncodeToString(sum[:8]) + ":" + c.TrackId
+}
+
+// Load reads the cache which hasn't expired
+func (c *LyricsCache) Load() bool {
+	jsonStr, err := NewTable().GetByKVModel(c)
+	if err != nil || len(jsonStr) == 0 {
+		return false
+	}
+	if err = json.Unmarshal(jsonStr, c); err != nil {
+		return false
+	}
+	ttl := types.LyricsCacheTTL
+	if c.NotFound {
+		ttl = types.LyricsNotFoundCacheTTL
+	}
+	return time.Since(c.CachedAt) < ttl
+}
+
+func (c *LyricsCache) Store() {
+	c.CachedAt = time.Now()
+	_ = NewTable().SetByKVModel(c, c)
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "testing"
+
+func TestLyricsCacheSource(t *testing.T) {
+	cache := LyricsCache{Provider: "http", Source: "https://a.example/{id}", TrackId: "track", Lyrics: "lyrics of a"}
+	cache.Store()
+
+	same := LyricsCache{Provider: "http", Source: "https://a.example/{id}", TrackId: "track"}
+	if !same.Load() || same.Lyrics != "lyrics of a" {
+		t.Errorf("lyrics = %q, expected the cache of same source", same.Lyrics)
+	}
+
+	other := LyricsCache{Provider: "http", Source: "https://b.example/{id}", TrackId: "track"}
+	if other.Load() {
+		t.Errorf("lyrics = %q, expected the cache of another source not read", other.Lyrics)
+	}
+}
@@ -1,13 +1,9 @@
 package storage
 
-import (
-	"github.com/go-musicfox/spotifox/internal/types"
-)
-
 type PlayMode struct{}
 
 func (p PlayMode) GetDbName() string {
-	return types.AppDBName
+	return ProfileDBName("")
 }
 
 func (p PlayMode) GetTableName() string {
@@ -3,7 +3,6 @@ package storage
 import (
 	"time"
 
-	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/zmb3/spotify/v2"
 )
 
@@ -15,7 +14,7 @@ type PlayerSnapshot struct {
 }
 
 func (p PlayerSnapshot) GetDbName() string {
-	return types.AppDBName
+	return ProfileDBName("")
 }
 
 func (p PlayerSnapshot) GetTableName() string {
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/pkg/errors"
+)
+
+// DefaultProfile uses the database of the single account before profiles are added,
+// so that nothing needs migrating
+const DefaultProfile = "default"
+
+var (
+	profileL   sync.RWMutex
+	curProfile = DefaultProfile
+
+	profileNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+)
+
+// CurProfile the profile whose data is read and written by the models of account
+func CurProfile() string {
+	profileL.RLock()
+	defer profileL.RUnlock()
+	return curProfile
+}
+
+func SetCurProfile(name string) {
+	profileL.Lock()
+	defer profileL.Unlock()
+	curProfile = name
+}
+
+// ProfileDBName database of the profile, empty name means current profile.
+// The names are prefixed so that they don't collide with other databases such as AppCacheDBName
+func ProfileDBName(name string) string {
+	if name == "" {
+		name = CurProfile()
+	}
+	if name == DefaultProfile {
+		return types.AppDBName
+	}
+	return types.AppDBName + "_profile_" + name
+}
+
+func CheckProfileName(name string) error {
+	if !profileNameRegexp.MatchString(name) {
+		return errors.Errorf("invalid profile name %q, only letters, digits, '-' and '_' are allowed", name)
+	}
+	return nil
+}
+
+// Profiles names of profiles and the last used one, stored in the database of default profile
+type Profiles struct {
+	Names []string `json:"names"`
+	Last  string   `json:"last"`
+}
+
+func (p Profiles) GetDbName() string {
+	return types.AppDBName
+}
+
+func (p Profiles) GetTableName() string {
+	return "default_bucket"
+}
+
+func (p Profiles) GetKey() string {
+	return "profiles"
+}
+
+// LoadProfiles the default profile is always included
+func LoadProfiles() Profiles {
+	var profiles Profiles
+	if jsonStr, err := NewTable().GetByKVModel(profiles); err == nil && len(jsonStr) > 0 {
+		_ = json.Unmarshal(jsonStr, &profiles)
+	}
+	if !profiles.Has(DefaultProfile) {
+		profiles.Names = append([]string{DefaultProfile}, profiles.Names...)
+	}
+	if profiles.Last == "" || !profiles.Has(profiles.Last) {
+		profiles.Last = DefaultProfile
+	}
+	return profiles
+}
+
+func (p Profiles) Has(name string) bool {
+	for _, v := range p.Names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Use adds the profile if not exists and marks it as the last used one
+func (p *Profiles) Use(name string) error {
+	if err := CheckProfileName(name); err != nil {
+		return err
+	}
+	if !p.Has(name) {
+		p.Names = append(p.Names, name)
+	}
+	p.Last = name
+	return NewTable().SetByKVModel(p, p)
+}
+
+// NextName a name not used by existing profiles
+func (p Profiles) NextName() string {
+	for i := len(p.Names) + 1; ; i++ {
+		name := "profile-" + strconv.Itoa(i)
+		if !p.Has(name) {
+			return name
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+func TestProfileDBName(t *testing.T) {
+	if name := ProfileDBName(DefaultProfile); name != types.AppDBName {
+		t.Errorf("db name = %s, expected the default profile to use %s", name, types.AppDBName)
+	}
+	if name := ProfileDBName("work"); name != "spotifox_profile_work" {
+		t.Errorf("db name = %s", name)
+	}
+
+	reserved := []string{types.AppDBName, types.AppCacheDBName}
+	for _, profile := range []string{"cache", "profile", "profile_cache", "work"} {
+		if err := CheckProfileName(profile); err != nil {
+			t.Fatal(err)
+		}
+		for _, db := range reserved {
+			if name := ProfileDBName(profile); name == db {
+				t.Errorf("profile %s uses the database %s", profile, db)
+			}
+		}
+	}
+
+	SetCurProfile("work")
+	defer SetCurProfile(DefaultProfile)
+	if name := ProfileDBName(""); name != "spotifox_profile_work" {
+		t.Errorf("db name = %s, expected the current profile", name)
+	}
+}
+
+func TestCheckProfileName(t *testing.T) {
+	for _, name := range []string{"", "a b", "../x", "名字", "a123456789012345678901234567890123"} {
+		if CheckProfileName(name) == nil {
+			t.Errorf("%q is valid, expected invalid", name)
+		}
+	}
+}
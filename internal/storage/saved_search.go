@@ -0,0 +1,21 @@
+package storage
+
+// SavedSearch named search query shown in main menu
+type SavedSearch struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+type SavedSearches []SavedSearch
+
+func (s SavedSearches) GetDbName() string {
+	return ProfileDBName("")
+}
+
+func (s SavedSearches) GetTableName() string {
+	return "default_bucket"
+}
+
+func (s SavedSearches) GetKey() string {
+	return "saved_searches"
+}
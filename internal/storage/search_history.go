@@ -0,0 +1,16 @@
+package storage
+
+// SearchHistory keywords of search, the latest first
+type SearchHistory struct{}
+
+func (h SearchHistory) GetDbName() string {
+	return ProfileDBName("")
+}
+
+func (h SearchHistory) GetTableName() string {
+	return "default_bucket"
+}
+
+func (h SearchHistory) GetKey() string {
+	return "search_history"
+}
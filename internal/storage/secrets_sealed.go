@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/go-musicfox/spotifox/internal/types"
+)
+
+// SecretsSealed marks that the secrets of the profile stored as plaintext before have been sealed
+type SecretsSealed struct{}
+
+func (s SecretsSealed) GetDbName() string {
+	return ProfileDBName("")
+}
+
+func (s SecretsSealed) GetTableName() string {
+	return "default_bucket"
+}
+
+func (s SecretsSealed) GetKey() string {
+	return "secrets_sealed"
+}
+
+// SecretStore the store of the key which sealed the secrets, the key is shared by all profiles
+type SecretStore struct{}
+
+func (s SecretStore) GetDbName() string {
+	return types.AppDBName
+}
+
+func (s SecretStore) GetTableName() string {
+	return "default_bucket"
+}
+
+func (s SecretStore) GetKey() string {
+	return "secret_store"
+}
+
+// Load returns the recorded store, empty if not recorded
+func (s SecretStore) Load() string {
+	var store string
+	if jsonStr, err := NewTable().GetByKVModel(s); err == nil && len(jsonStr) > 0 {
+		_ = json.Unmarshal(jsonStr, &store)
+	}
+	return store
+}
+
+// Store records the store of key
+func (s SecretStore) Store(store string) error {
+	return NewTable().SetByKVModel(s, store)
+}
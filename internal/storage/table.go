@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/go-musicfox/spotifox/utils"
@@ -163,9 +164,53 @@ func (table *table) GetByKVModel(model KVModel) ([]byte, error) {
 	return table.Get(model, []byte(model.GetKey()))
 }
 
+// ErrBucketNotExists the table hasn't been created
+var ErrBucketNotExists = errors.New("not exists")
+
 func checkBucket(bucket *bbolt.Bucket, bucketName string) error {
 	if bucket == nil {
-		return errors.Errorf("Bucket(%s) not exists!", bucketName)
+		return errors.Wrapf(ErrBucketNotExists, "Bucket(%s)", bucketName)
 	}
 	return nil
 }
+
+// DeletePrefix delete lines whose key has the prefix
+func (table *table) DeletePrefix(model Model, prefix []byte) (err error) {
+	localDB, err := DBManager.GetDBFromCache(model)
+	if err != nil {
+		return
+	}
+
+	err = localDB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(model.GetTableName()))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Seek(prefix) {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// DeleteTable delete all lines of the table
+func (table *table) DeleteTable(model Model) (err error) {
+	localDB, err := DBManager.GetDBFromCache(model)
+	if err != nil {
+		return
+	}
+
+	err = localDB.Update(func(tx *bbolt.Tx) error {
+		err := tx.DeleteBucket([]byte(model.GetTableName()))
+		if errors.Is(err, bbolt.ErrBucketNotFound) {
+			return nil
+		}
+		return err
+	})
+	return
+}
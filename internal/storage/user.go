@@ -1,13 +1,12 @@
 package storage
 
-import (
-	"github.com/go-musicfox/spotifox/internal/types"
-)
-
-type User struct{}
+// User the logged-in account of the profile, empty profile means current profile
+type User struct {
+	Profile string
+}
 
 func (u User) GetDbName() string {
-	return types.AppDBName
+	return ProfileDBName(u.Profile)
 }
 
 func (u User) GetTableName() string {
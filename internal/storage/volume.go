@@ -1,9 +1,5 @@
 package storage
 
-import (
-	"github.com/go-musicfox/spotifox/internal/types"
-)
-
 type VolumeStorable interface {
 	Volume() int
 	SetVolume(volume int)
@@ -12,7 +8,7 @@ type VolumeStorable interface {
 type Volume struct{}
 
 func (v Volume) GetDbName() string {
-	return types.AppDBName
+	return ProfileDBName("")
 }
 
 func (v Volume) GetTableName() string {
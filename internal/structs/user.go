@@ -5,6 +5,7 @@ import (
 
 	respot "github.com/arcspace/go-librespot/librespot/api-respot"
 	"github.com/arcspace/go-librespot/librespot/mercury"
+	"github.com/go-musicfox/spotifox/utils/secret"
 	"github.com/pkg/errors"
 	"github.com/zmb3/spotify/v2"
 )
@@ -14,8 +15,10 @@ type User struct {
 
 	Username string        `json:"username"`
 	Country  string        `json:"country"`
-	AuthBlob []byte        `json:"authBlob"`
+	AuthBlob secret.Bytes  `json:"authBlob"`
 	Token    mercury.Token `json:"-"`
+	// RefreshToken of browser login, used when the session can't get token
+	RefreshToken secret.String `json:"refreshToken,omitempty"`
 
 	Email     string `json:"email"`
 	Product   string `json:"product"`
@@ -15,20 +15,59 @@ var (
 const AppName = "spotifox"
 const GroupID = "com.go-musicfox.spotifox"
 const SpotifyDeviceName = "Spotifox"
-const SpotifyOAuthScopes = "streaming,playlist-read,playlist-read-private,playlist-read-collaborative,playlist-modify-private,playlist-modify-public,user-top-read,user-read-recently-played,user-library-modify,user-library-read,user-read-private,user-follow-modify,user-follow-read"
+const SpotifyAuthURL = "https://accounts.spotify.com/authorize"
+const SpotifyTokenURL = "https://accounts.spotify.com/api/token"
+const SpotifyOAuthPort = 8898
+const SpotifyOAuthTimeout = time.Minute * 5
+const SpotifyOAuthScopes = "streaming,playlist-read,playlist-read-private,playlist-read-collaborative,playlist-modify-private,playlist-modify-public,user-top-read,user-read-recently-played,user-library-modify,user-library-read,user-read-private,user-follow-modify,user-follow-read,user-read-playback-state,user-modify-playback-state"
+
+// SpotifyWebAuthScopes scopes of browser login, "playlist-read" is only known by the access point
+const SpotifyWebAuthScopes = "streaming,playlist-read-private,playlist-read-collaborative,playlist-modify-private,playlist-modify-public,user-top-read,user-read-recently-played,user-library-modify,user-library-read,user-read-private,user-follow-modify,user-follow-read,user-read-playback-state,user-modify-playback-state"
+
 const AppDescription = "<cyan>Spotifox - Using Spotify on the Command Line</>"
 const AppGithubUrl = "https://github.com/go-musicfox/spotifox"
 const AppLatestReleases = "https://github.com/go-musicfox/spotifox/releases/latest"
 const AppCheckUpdateUrl = "https://api.github.com/repos/go-musicfox/spotifox/releases/latest"
 const LastfmAuthUrl = "https://www.last.fm/api/auth/?api_key=%s&token=%s"
+const LastfmScrobbleBatchSize = 50
+const LastfmScrobbleRetryMin = time.Second * 30
+const LastfmScrobbleRetryMax = time.Minute * 30
+const LastfmScrobbleMinDuration = time.Second * 30
+const LastfmScrobbleEnoughListened = time.Minute * 4
+const LastfmLovedTracksPageSize = 1000
+const LastfmLoveSearchLimit = 5
+const LastfmLoveMaxAttempts = 3
+const LastfmLoveRetryInterval = time.Second * 2
+const ListenedMaxTickGap = time.Second
 const ProgressFullChar = "#"
 const ProgressEmptyChar = "."
 const StartupLoadingSeconds = 2
 const StartupTickDuration = time.Millisecond * 16
+const KaraokeTickDuration = time.Millisecond * 50
+const LyricsPageSnapBackDuration = time.Second * 3
+const LyricOffsetStep = time.Millisecond * 100
+const LyricOffsetMax = time.Second * 10
+const LyricOffsetTipsDuration = time.Second * 2
 
 const AppLocalDataDir = "spotifox"
 const AppDBName = "spotifox"
+const AppCacheDBName = "spotifox_cache"
+const LyricsCacheTTL = time.Hour * 24 * 30
+const LyricsNotFoundCacheTTL = time.Hour * 24
+const LyricsDir = "lyrics"
+const LyricsProviderLocal = "local"
+const LyricsProviderSpotify = "spotify"
+const LyricsProviderLRCLIB = "lrclib"
+const LyricsProviderHTTP = "http"
+const LyricsProviders = "local,spotify"
+const SecretStoreAuto = "auto"
+const SecretKeyFile = "secret.key"
+const SecretPassphraseFile = "secret_passphrase.json"
+const SecretPassphraseEnv = "SPOTIFOX_PASSPHRASE"
 const AppIniFile = "spotifox.ini"
+
+// AppProfileIniFile settings of the profile, which override the ones of AppIniFile
+const AppProfileIniFile = "spotifox_%s.ini"
 const AppPrimaryRandom = "random"
 const AppPrimaryColor = "#f90022"
 const AppHttpTimeout = time.Second * 10
@@ -35,7 +35,7 @@ func (m *CurPlaylist) GetMenuKey() string {
 }
 
 func (m *CurPlaylist) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *CurPlaylist) Songs() []spotify.FullTrack {
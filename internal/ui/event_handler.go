@@ -7,6 +7,7 @@ import (
 	"github.com/anhoder/foxful-cli/model"
 	tea "github.com/charmbracelet/bubbletea"
 	playerpkg "github.com/go-musicfox/spotifox/internal/player"
+	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
 	"github.com/zmb3/spotify/v2"
@@ -42,24 +43,36 @@ func (h *EventHandler) KeyMsgHandle(msg tea.KeyMsg, a *model.App) (bool, model.P
 			player.LocatePlayingSong()
 		}
 	case " ", "　":
+		if player.InRemoteMode() && !h.selectedPlayable() {
+			go utils.PanicRecoverWrapper(false, player.RemoteToggle)
+			break
+		}
 		newPage := h.spaceKeyHandle()
 		if newPage != nil {
 			return true, newPage, func() tea.Msg { return newPage.Msg() }
 		}
 	case "v":
-		player.Seek(player.PassedTime() + time.Second*5)
+		h.seekBy(time.Second * 5)
 	case "V":
-		player.Seek(player.PassedTime() + time.Second*10)
+		h.seekBy(time.Second * 10)
 	case "x":
-		player.Seek(player.PassedTime() - time.Second*1)
+		h.seekBy(-time.Second * 1)
 	case "X":
-		player.Seek(player.PassedTime() - time.Second*5)
+		h.seekBy(-time.Second * 5)
 	case "[", "【":
+		if player.InRemoteMode() {
+			go utils.PanicRecoverWrapper(false, player.RemotePrevious)
+			break
+		}
 		newPage := player.PreviousSong(true)
 		if newPage != nil {
 			return true, newPage, func() tea.Msg { return newPage.Msg() }
 		}
 	case "]", "】":
+		if player.InRemoteMode() {
+			go utils.PanicRecoverWrapper(false, player.RemoteNext)
+			break
+		}
 		newPage := player.NextSong(true)
 		if newPage != nil {
 			return true, newPage, func() tea.Msg { return newPage.Msg() }
@@ -79,8 +92,16 @@ func (h *EventHandler) KeyMsgHandle(msg tea.KeyMsg, a *model.App) (bool, model.P
 		logout(true)
 		return true, nil, tea.Quit
 	case "-", "−", "ー": // half-width, full-width and katakana
+		if player.InRemoteMode() {
+			go utils.PanicRecoverWrapper(false, player.RemoteDownVolume)
+			break
+		}
 		player.DownVolume()
 	case "=", "＝":
+		if player.InRemoteMode() {
+			go utils.PanicRecoverWrapper(false, player.RemoteUpVolume)
+			break
+		}
 		player.UpVolume()
 	case "<", "〈", "＜", "《", "«": // half-width, full-width, Japanese, Chinese and French
 		// like selected song
@@ -122,6 +143,34 @@ func (h *EventHandler) KeyMsgHandle(msg tea.KeyMsg, a *model.App) (bool, model.P
 	case "'", "\"":
 		newPage := followSelectedPlaylist(h.spotifox, false)
 		return true, newPage, a.Tick(time.Nanosecond)
+	case "e":
+		newPage := saveAlbumOfPlayingSong(h.spotifox, true)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "E":
+		newPage := saveSelectedAlbum(h.spotifox, true)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "u":
+		newPage := saveAlbumOfPlayingSong(h.spotifox, false)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "U":
+		newPage := saveSelectedAlbum(h.spotifox, false)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "i":
+		newPage := trackInfoOfPlayingSong(h.spotifox)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "I":
+		newPage := trackInfoOfSelectedSong(h.spotifox)
+		return true, newPage, a.Tick(time.Nanosecond)
+	case "ctrl+o":
+		newPage, cmd := h.spotifox.ToOpenLinkPage()
+		return true, newPage, cmd
+	case "{", "「":
+		player.AdjustLyricOffset(-types.LyricOffsetStep)
+	case "}", "」":
+		player.AdjustLyricOffset(types.LyricOffsetStep)
+	case "y", "Y":
+		newPage, cmd := h.spotifox.ToLyricsPage()
+		return true, newPage, cmd
 	case "r", "R":
 		// rerender
 		return true, main, a.RerenderCmd(true)
@@ -132,6 +181,16 @@ func (h *EventHandler) KeyMsgHandle(msg tea.KeyMsg, a *model.App) (bool, model.P
 	return true, nil, nil
 }
 
+// seekBy seeks the playing song by delta, on the followed device in remote mode
+func (h *EventHandler) seekBy(delta time.Duration) {
+	player := h.spotifox.player
+	if player.InRemoteMode() {
+		go utils.PanicRecoverWrapper(false, func() { player.RemoteSeekBy(delta) })
+		return
+	}
+	player.Seek(player.PassedTime() + delta)
+}
+
 func (h *EventHandler) enterKeyHandle() (stopPropagation bool, newPage model.Page, cmd tea.Cmd) {
 	loading := model.NewLoading(h.spotifox.MustMain())
 	loading.Start()
@@ -145,6 +204,19 @@ func (h *EventHandler) enterKeyHandle() (stopPropagation bool, newPage model.Pag
 	return false, nil, nil
 }
 
+// selectedPlayable whether the selected item of current menu is a song to play
+func (h *EventHandler) selectedPlayable() bool {
+	var (
+		main = h.spotifox.MustMain()
+		menu = main.CurMenu()
+	)
+	me, ok := menu.(SongsMenu)
+	if !ok || !me.IsPlayable() {
+		return false
+	}
+	return menu.RealDataIndex(main.SelectedIndex()) < len(me.Songs())
+}
+
 func (h *EventHandler) spaceKeyHandle() model.Page {
 	var (
 		songs         []spotify.FullTrack
@@ -174,6 +246,10 @@ func (h *EventHandler) spaceKeyHandle() model.Page {
 	}
 
 	if inPlayingMenu && utils.CompareSong(songs[selectedIndex], player.playlist[player.curSongIndex]) {
+		if player.InRemoteMode() {
+			go utils.PanicRecoverWrapper(false, player.RemoteToggle)
+			return nil
+		}
 		switch player.State() {
 		case playerpkg.Paused:
 			player.Resume()
@@ -194,6 +270,9 @@ func (h *EventHandler) spaceKeyHandle() model.Page {
 	player.playlist = newPlaylists
 
 	player.playlistUpdateAt = time.Now()
+	if me, ok := menu.(FullLoadableMenu); ok {
+		player.LoadFullPlaylist(me)
+	}
 	return player.PlaySong(player.playlist[selectedIndex], DurationNext)
 }
 
@@ -207,6 +286,14 @@ func (h *EventHandler) MouseMsgHandle(msg tea.MouseMsg, a *model.App) (stopPropa
 		x, y := msg.X, msg.Y
 		w := len(player.progressRamp)
 		if y+1 == a.WindowHeight() && x+1 <= len(player.progressRamp) {
+			if _, state, _, ok := player.RemoteSnapshot(); ok {
+				if state == nil || state.Item == nil || state.Item.Duration == 0 {
+					return true, main, nil
+				}
+				position := time.Duration(float64(x) * float64(state.Item.TimeDuration()) / float64(w))
+				go utils.PanicRecoverWrapper(false, func() { player.RemoteSeek(position) })
+				return true, main, a.Tick(time.Nanosecond)
+			}
 			allDuration := int(player.CurMusic().Duration().Seconds())
 			if allDuration == 0 {
 				return true, main, nil
@@ -5,6 +5,7 @@ import (
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
 
 	"github.com/skratchdot/open-golang/open"
@@ -34,6 +35,11 @@ func (m *Lastfm) MenuViews() []model.MenuItem {
 	}
 	return []model.MenuItem{
 		{Title: locale.MustT("view_user_info")},
+		{
+			Title:    locale.MustT("flush_scrobbles"),
+			Subtitle: locale.MustT("pending_scrobbles", locale.WithTplData(map[string]int{"Count": m.spotifox.lastfm.ScrobbleQueue().Pending()})),
+		},
+		{Title: locale.MustT("sync_loved_tracks")},
 		{Title: locale.MustT("clear_auth")},
 	}
 }
@@ -46,13 +52,39 @@ func (m *Lastfm) SubMenu(_ *model.App, index int) model.Menu {
 	case 0:
 		_ = open.Start(m.spotifox.lastfmUser.Url)
 	case 1:
+		m.flushScrobbles()
+	case 2:
+		return NewLastfmLoveSyncMenu(m.baseMenu)
+	case 3:
 		m.spotifox.lastfmUser = &storage.LastfmUser{}
 		m.spotifox.lastfmUser.Clear()
+		// the scrobbles aren't submitted without session, so they're dropped as well
+		m.spotifox.lastfm.SetSession("")
+		if err := m.spotifox.lastfm.ScrobbleQueue().Clear(); err != nil {
+			utils.Logger().Printf("[ERROR] %+v", err)
+		}
 		return NewLastfmRes(m.baseMenu, locale.MustT("clear_auth"), nil, 2)
 	}
 	return nil
 }
 
+// flushScrobbles submits the pending scrobbles now
+func (m *Lastfm) flushScrobbles() {
+	main := m.spotifox.MustMain()
+	loading := model.NewLoading(main)
+	loading.Start()
+	submitted, err := m.spotifox.lastfm.ScrobbleQueue().Flush()
+	loading.Complete()
+
+	main.RefreshMenuList()
+	if err != nil {
+		utils.Logger().Printf("flush scrobbles failed: %+v", err)
+		model.NewMenuTips(main, nil).DisplayTips("Err:" + err.Error())
+		return
+	}
+	model.NewMenuTips(main, nil).DisplayTips(locale.MustT("scrobbles_flushed", locale.WithTplData(map[string]int{"Count": submitted})))
+}
+
 func (m *Lastfm) FormatMenuItem(item *model.MenuItem) {
 	if m.spotifox.lastfmUser == nil || m.spotifox.lastfmUser.SessionKey == "" {
 		item.Subtitle = "[" + locale.MustT("unauth") + "]"
@@ -5,6 +5,7 @@ import (
 	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/go-musicfox/spotifox/utils/secret"
 
 	"github.com/skratchdot/open-golang/open"
 )
@@ -31,10 +32,10 @@ func (m *LastfmAuth) MenuViews() []model.MenuItem {
 }
 
 func (m *LastfmAuth) BeforeBackMenuHook() model.Hook {
-	return func(_ *model.Main) (bool, model.Page) {
+	return m.backMenuHook(func(_ *model.Main) (bool, model.Page) {
 		m.token, m.url, m.err = "", "", nil
 		return true, nil
-	}
+	})
 }
 
 func (m *LastfmAuth) BeforeEnterMenuHook() model.Hook {
@@ -57,7 +58,8 @@ func (m *LastfmAuth) SubMenu(mod_el *model.App, _ int) model.Menu {
 	if m.spotifox.lastfmUser == nil {
 		m.spotifox.lastfmUser = &storage.LastfmUser{}
 	}
-	m.spotifox.lastfmUser.SessionKey, err = m.spotifox.lastfm.GetSession(m.token)
+	sessionKey, err := m.spotifox.lastfm.GetSession(m.token)
+	m.spotifox.lastfmUser.SessionKey = secret.String(sessionKey)
 	if err != nil {
 		loading.Complete()
 		return NewLastfmRes(m.baseMenu, locale.MustT("auth"), err, 1)
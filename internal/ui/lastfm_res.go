@@ -42,10 +42,10 @@ func (m *LastfmRes) SubMenu(app *model.App, _ int) model.Menu {
 }
 
 func (m *LastfmRes) BeforeBackMenuHook() model.Hook {
-	return func(main *model.Main) (bool, model.Page) {
+	return m.backMenuHook(func(main *model.Main) (bool, model.Page) {
 		m.opName, m.err, m.backLevel = "", nil, 0
 		return true, nil
-	}
+	})
 }
 
 func (m *LastfmRes) FormatMenuItem(item *model.MenuItem) {
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+const likedSongsSyncPageSize = 50
+
+// likedSongsIndex local set of user's saved track ids, avoid checking every track over network
+type likedSongsIndex struct {
+	l             sync.RWMutex
+	userId        string
+	latestAddedAt string
+	ids           map[spotify.ID]struct{}
+	// ready whether the index has been synced with spotify once
+	ready   bool
+	syncing atomic.Bool
+}
+
+// Load reads the index of the user from local db
+func (i *likedSongsIndex) Load(userId string) {
+	i.l.Lock()
+	defer i.l.Unlock()
+
+	i.userId, i.latestAddedAt, i.ready = userId, "", false
+	i.ids = make(map[spotify.ID]struct{})
+
+	var liked storage.LikedSongs
+	jsonStr, err := storage.NewTable().GetByKVModel(liked)
+	if err != nil || len(jsonStr) == 0 {
+		return
+	}
+	if err = json.Unmarshal(jsonStr, &liked); err != nil || liked.UserId != userId {
+		return
+	}
+	for _, id := range liked.Ids {
+		i.ids[id] = struct{}{}
+	}
+	i.latestAddedAt, i.ready = liked.LatestAddedAt, true
+}
+
+// Liked reports whether the track is liked, known is false if the index hasn't been synced yet
+func (i *likedSongsIndex) Liked(id spotify.ID) (liked, known bool) {
+	i.l.RLock()
+	defer i.l.RUnlock()
+	_, liked = i.ids[id]
+	return liked, i.ready
+}
+
+// Set updates the index after liking or disliking a track
+func (i *likedSongsIndex) Set(id spotify.ID, likeOrNot bool) {
+	i.l.Lock()
+	if i.ids == nil {
+		i.ids = make(map[spotify.ID]struct{})
+	}
+	if likeOrNot {
+		i.ids[id] = struct{}{}
+	} else {
+		delete(i.ids, id)
+	}
+	i.l.Unlock()
+	i.save()
+}
+
+// Sync fetches saved tracks newer than the index, all saved tracks are fetched
+// again when the count doesn't match (tracks were removed by other clients)
+func (i *likedSongsIndex) Sync(ctx context.Context, client *spotify.Client) error {
+	if client == nil || !i.syncing.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer i.syncing.Store(false)
+
+	i.l.RLock()
+	latestAddedAt, count := i.latestAddedAt, len(i.ids)
+	i.l.RUnlock()
+
+	var (
+		newIds    []spotify.ID
+		newLatest = latestAddedAt
+		total     int
+	)
+	for offset := 0; ; offset += likedSongsSyncPageSize {
+		res, err := client.CurrentUsersTracks(ctx, spotify.Limit(likedSongsSyncPageSize), spotify.Offset(offset))
+		if err != nil {
+			return errors.Wrap(err, "sync liked songs failed")
+		}
+		total = res.Total
+
+		reached := false
+		for _, t := range res.Tracks {
+			// saved tracks are sorted by added time desc
+			if latestAddedAt != "" && t.AddedAt <= latestAddedAt {
+				reached = true
+				break
+			}
+			newIds = append(newIds, t.ID)
+			if t.AddedAt > newLatest {
+				newLatest = t.AddedAt
+			}
+		}
+		if reached || len(res.Tracks) < likedSongsSyncPageSize || offset+len(res.Tracks) >= total {
+			break
+		}
+	}
+
+	i.l.Lock()
+	if latestAddedAt == "" {
+		// full sync, drop tracks which are not saved anymore
+		i.ids = make(map[spotify.ID]struct{}, len(newIds))
+	}
+	for _, id := range newIds {
+		i.ids[id] = struct{}{}
+	}
+	i.latestAddedAt = newLatest
+	mismatched := len(i.ids) != total && latestAddedAt != ""
+	if mismatched {
+		i.latestAddedAt = ""
+	}
+	i.ready = !mismatched
+	i.l.Unlock()
+
+	utils.Logger().Printf("[INFO] liked songs synced, before: %d, new: %d, total: %d", count, len(newIds), total)
+	if mismatched {
+		i.syncing.Store(false)
+		return i.Sync(ctx, client)
+	}
+	i.save()
+	return nil
+}
+
+func (i *likedSongsIndex) save() {
+	i.l.RLock()
+	liked := storage.LikedSongs{
+		UserId:        i.userId,
+		LatestAddedAt: i.latestAddedAt,
+		Ids:           make([]spotify.ID, 0, len(i.ids)),
+	}
+	for id := range i.ids {
+		liked.Ids = append(liked.Ids, id)
+	}
+	i.l.RUnlock()
+
+	if liked.UserId == "" {
+		return
+	}
+	_ = storage.NewTable().SetByKVModel(liked, liked)
+}
+
+// markLiked prefixes liked songs with a heart, menus are not modified
+func (i *likedSongsIndex) markLiked(menus []model.MenuItem, songs []spotify.FullTrack) []model.MenuItem {
+	i.l.RLock()
+	defer i.l.RUnlock()
+	if len(i.ids) == 0 {
+		return menus
+	}
+
+	marked := make([]model.MenuItem, len(menus))
+	copy(marked, menus)
+	for j := range marked {
+		if j >= len(songs) {
+			break
+		}
+		if _, ok := i.ids[songs[j].ID]; ok {
+			marked[j].Title = "♥ " + marked[j].Title
+		}
+	}
+	return marked
+}
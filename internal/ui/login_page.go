@@ -3,22 +3,27 @@ package ui
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/anhoder/foxful-cli/util"
+	respot "github.com/arcspace/go-librespot/librespot/api-respot"
 	_ "github.com/arcspace/go-librespot/librespot/core" // bootstrapping
+	"github.com/arcspace/go-librespot/librespot/mercury"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/connect"
 	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/internal/structs"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/auth"
 	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/go-musicfox/spotifox/utils/secret"
 	"github.com/mattn/go-runewidth"
 	"github.com/muesli/termenv"
 	"github.com/zmb3/spotify/v2"
@@ -29,7 +34,7 @@ const LoginPageType model.PageType = "login"
 
 const (
 	submitIndex = 2 // skip account and password input
-	// authIndex   = 3
+	authIndex   = 3
 )
 
 // login tick
@@ -49,13 +54,22 @@ type LoginPage struct {
 	accountInput  textinput.Model
 	passwordInput textinput.Model
 	submitButton  string
-	// authButton    string
-	// authStep int
-	tips string
+	authButton    string
+	tips          string
+
+	// browser login in progress
+	oauthCancel  context.CancelFunc
+	oauthResult  chan oauthResult
+	refreshToken string
 
 	AfterLogin LoginCallback
 }
 
+type oauthResult struct {
+	token *oauth2.Token
+	err   error
+}
+
 func NewLoginPage(spotifox *Spotifox) (login *LoginPage) {
 	accountInput := textinput.New()
 	accountInput.Placeholder = " " + locale.MustT("account")
@@ -79,7 +93,7 @@ func NewLoginPage(spotifox *Spotifox) (login *LoginPage) {
 		passwordInput: passwordInput,
 		submitButton:  model.GetBlurredSubmitButton(),
 	}
-	// login.authButton = model.GetBlurredButton(login.authButtonTextByStep())
+	login.authButton = model.GetBlurredButton(locale.MustT("login_in_browser"))
 
 	return
 }
@@ -104,7 +118,7 @@ func (l *LoginPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 	)
 
 	if _, ok = msg.(tickLoginMsg); ok {
-		return l, nil
+		return l.checkOAuthResult()
 	}
 
 	if key, ok = msg.(tea.KeyMsg); !ok {
@@ -113,19 +127,13 @@ func (l *LoginPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 
 	switch key.String() {
 	case "b":
-		// if l.index != submitIndex && l.index != authIndex {
-		if l.index != submitIndex {
+		if l.index != submitIndex && l.index != authIndex {
 			return l.updateLoginInputs(msg)
 		}
 		fallthrough
 	case "esc":
 		l.tips = ""
-		// l.authStep = 0
-		// if l.index == authIndex {
-		// 	l.authButton = model.GetFocusedButton(l.authButtonTextByStep())
-		// } else {
-		// 	l.authButton = model.GetBlurredButton(l.authButtonTextByStep())
-		// }
+		l.cancelOAuth()
 		return l.spotifox.MustMain(), l.spotifox.RerenderCmd(true)
 	case "tab", "shift+tab", "enter", "up", "down", "left", "right":
 		s := key.String()
@@ -141,21 +149,21 @@ func (l *LoginPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 			if l.index < submitIndex {
 				return l.updateLoginInputs(msg)
 			}
-			// if s == "left" && l.index == authIndex {
-			// 	l.index--
-			// } else if s == "right" && l.index == submitIndex {
-			// 	l.index++
-			// }
+			if s == "left" && l.index == authIndex {
+				l.index--
+			} else if s == "right" && l.index == submitIndex {
+				l.index++
+			}
 		} else if s == "up" || s == "shift+tab" {
 			l.index--
 		} else {
 			l.index++
 		}
 
-		if l.index > submitIndex {
+		if l.index > authIndex {
 			l.index = 0
 		} else if l.index < 0 {
-			l.index = submitIndex
+			l.index = authIndex
 		}
 
 		for i := 0; i <= len(inputs)-1; i++ {
@@ -181,11 +189,11 @@ func (l *LoginPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 			l.submitButton = model.GetBlurredSubmitButton()
 		}
 
-		// if l.index == authIndex {
-		// 	l.authButton = model.GetFocusedButton(l.authButtonTextByStep())
-		// } else {
-		// 	l.authButton = model.GetBlurredButton(l.authButtonTextByStep())
-		// }
+		if l.index == authIndex {
+			l.authButton = model.GetFocusedButton(locale.MustT("login_in_browser"))
+		} else {
+			l.authButton = model.GetBlurredButton(locale.MustT("login_in_browser"))
+		}
 
 		return l, nil
 	}
@@ -258,10 +266,9 @@ func (l *LoginPage) View(a *model.App) string {
 
 	var btnBlank = "    "
 	builder.WriteString(btnBlank)
-	// builder.WriteString(l.authButton)
+	builder.WriteString(l.authButton)
 
-	// spaceLen := a.WindowWidth() - mainPage.MenuStartColumn() - runewidth.StringWidth(model.SubmitText) - runewidth.StringWidth(l.authButtonTextByStep()) - len(btnBlank)
-	spaceLen := a.WindowWidth() - mainPage.MenuStartColumn() - runewidth.StringWidth(locale.MustT("submit_text")) - len(btnBlank)
+	spaceLen := a.WindowWidth() - mainPage.MenuStartColumn() - runewidth.StringWidth(locale.MustT("submit_text")) - runewidth.StringWidth(locale.MustT("login_in_browser")) - len(btnBlank)
 	if spaceLen > 0 {
 		builder.WriteString(strings.Repeat(" ", spaceLen))
 	}
@@ -293,17 +300,6 @@ func (l *LoginPage) updateLoginInputs(msg tea.Msg) (model.Page, tea.Cmd) {
 	return l, tea.Batch(cmds...)
 }
 
-// func (l *LoginPage) authButtonTextByStep() string {
-// 	switch l.authStep {
-// 	case 1:
-// 		return "已在浏览器登录授权，继续"
-// 	case 0:
-// 		fallthrough
-// 	default:
-// 		return "OAuth授权"
-// 	}
-// }
-
 func (l *LoginPage) enterHandler() (model.Page, tea.Cmd) {
 	loading := model.NewLoading(l.spotifox.MustMain(), l.menuTitle)
 	loading.DisplayNotOnlyOnMain()
@@ -317,64 +313,109 @@ func (l *LoginPage) enterHandler() (model.Page, tea.Cmd) {
 			return l, nil
 		}
 		return l.loginByAccount()
-		// case authIndex:
-		//return l.loginByOAuth()
+	case authIndex:
+		return l.loginByOAuth()
 	}
 
 	return l, tickLogin(time.Nanosecond)
 }
 
 func (l *LoginPage) loginByAccount() (model.Page, tea.Cmd) {
-	login := &l.spotifox.sess.Context().Login
-	login.Username = l.accountInput.Value()
-	login.Password = l.passwordInput.Value()
-	if err := l.spotifox.sess.Login(); err != nil {
+	err := l.spotifox.withSession(func(sess respot.Session) error {
+		login := &sess.Context().Login
+		login.Username = l.accountInput.Value()
+		login.Password = l.passwordInput.Value()
+		return sess.Login()
+	})
+	if err != nil {
 		return l.handleLoginFail(err)
 	}
 
 	return l.handleLoginSuccess()
 }
 
-// func (l *LoginPage) loginByOAuth() (model.Page, tea.Cmd) {
-//if l.authStep == 0 {
-//	var authURL string
-//	authURL, l.tokenChan = core.StartOAuth(constants.SpotifyClientId, constants.SpotifyClientSecret, constants.SpotifyOAuthScopes, constants.SpotifyOAuthPort)
-//	_ = open.Start(authURL)
-//	l.authStep++
-//	return l, tickLogin(time.Nanosecond)
-//}
-//
-//if l.tokenChan == nil {
-//	utils.Logger().Print("auth failed: token chan is nil")
-//	l.tips = util.SetFgStyle("Not Auth", termenv.ANSIBrightRed)
-//	return l, tickLogin(time.Nanosecond)
-//}
-//var accessToken string
-//select {
-//case auth := <-l.tokenChan:
-//	accessToken = auth.AccessToken
-//case <-time.After(time.Second * 3):
-//}
-//if accessToken == "" {
-//	utils.Logger().Print("auth failed: token is empty")
-//	l.tips = util.SetFgStyle("Not Auth", termenv.ANSIBrightRed)
-//	return l, tickLogin(time.Nanosecond)
-//}
-//session, err := core.LoginOAuthToken(accessToken, constants.SpotifyDeviceName)
-//if err != nil {
-//	utils.Logger().Printf("auth failed, get session err: %+v", err)
-//	l.tips = util.SetFgStyle(err.Error(), termenv.ANSIBrightRed)
-//	return l, tickLogin(time.Nanosecond)
-//}
-//
-//l.tips = ""
-//return l.loginSuccessHandle(structs.NewUserFromSession(session))
-// }
+// loginByOAuth opens the authorize page in browser, the result is checked when ticking
+func (l *LoginPage) loginByOAuth() (model.Page, tea.Cmd) {
+	if l.oauthResult != nil {
+		return l, nil
+	}
+
+	var ctx context.Context
+	ctx, l.oauthCancel = context.WithTimeout(context.Background(), types.SpotifyOAuthTimeout)
+	l.oauthResult = make(chan oauthResult, 1)
+	l.tips = util.SetFgStyle(locale.MustT("waiting_browser_login"), termenv.ANSIBrightBlack)
+
+	conf := l.spotifox.oauthConfig()
+	result := l.oauthResult
+	go utils.PanicRecoverWrapper(false, func() {
+		token, err := conf.Authorize(ctx)
+		result <- oauthResult{token: token, err: err}
+		l.spotifox.Rerender(false)
+	})
+	return l, tickLogin(time.Nanosecond)
+}
+
+func (l *LoginPage) cancelOAuth() {
+	if l.oauthCancel != nil {
+		l.oauthCancel()
+	}
+	l.oauthCancel, l.oauthResult = nil, nil
+}
+
+func (l *LoginPage) checkOAuthResult() (model.Page, tea.Cmd) {
+	if l.oauthResult == nil {
+		return l, nil
+	}
+	var res oauthResult
+	select {
+	case res = <-l.oauthResult:
+	default:
+		return l, nil
+	}
+	l.cancelOAuth()
+	if res.err != nil {
+		return l.handleLoginFail(res.err)
+	}
+
+	loading := model.NewLoading(l.spotifox.MustMain(), l.menuTitle)
+	loading.DisplayNotOnlyOnMain()
+	loading.Start()
+	defer loading.Complete()
+	return l.loginByToken(res.token)
+}
+
+// loginByToken bootstraps the session from the access token of browser login
+func (l *LoginPage) loginByToken(token *oauth2.Token) (model.Page, tea.Cmd) {
+	err := l.spotifox.ReconnSessionWhenNeed(func(sess respot.Session) error {
+		login := &sess.Context().Login
+		login.Username, login.Password, login.AuthData = "", "", nil
+		login.AuthToken = token.AccessToken
+		return sess.Login()
+	})
+	if err != nil {
+		return l.handleLoginFail(err)
+	}
+	l.refreshToken = token.RefreshToken
+	return l.handleLoginSuccess()
+}
 
 func (l *LoginPage) handleLoginSuccess() (model.Page, tea.Cmd) {
-	user := structs.NewUserFromSession(l.spotifox.sess.Context().Info)
+	var (
+		user  structs.User
+		token *mercury.Token
+	)
+	err := l.spotifox.withSession(func(sess respot.Session) (err error) {
+		user = structs.NewUserFromSession(sess.Context().Info)
+		token, err = sess.Mercury().GetToken(configs.ConfigRegistry.Spotify.ClientId, types.SpotifyOAuthScopes)
+		return
+	})
+	// keep the refresh token of browser login
+	if l.refreshToken != "" {
+		user.RefreshToken, l.refreshToken = secret.String(l.refreshToken), ""
+	} else if l.spotifox.user != nil && l.spotifox.user.Username == user.Username {
+		user.RefreshToken = l.spotifox.user.RefreshToken
+	}
 
-	token, err := l.spotifox.sess.Mercury().GetToken(configs.ConfigRegistry.Spotify.ClientId, types.SpotifyOAuthScopes)
 	if err != nil {
 		return l.handleLoginFail(err)
 	}
@@ -383,13 +424,11 @@ func (l *LoginPage) handleLoginSuccess() (model.Page, tea.Cmd) {
 	}
 	user.Token = *token
 
-	httpClient := oauth2.NewClient(context.Background(), (*auth.TokenSourceWrapper)(&oauth2.Token{
-		AccessToken: token.AccessToken,
-		TokenType:   token.TokenType,
-		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn-30) * time.Second),
-	}))
-	httpClient.Timeout = types.AppHttpTimeout
+	// requests go through the cache and retry transports, token is refreshed ahead of expiry
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: l.spotifox.httpCache})
+	httpClient := oauth2.NewClient(ctx, auth.NewRefreshTokenSource(oauth2TokenOf(token), l.spotifox.tokenRefresher(l.spotifox.credentialsOf(&user))))
 	l.spotifox.spotifyClient = spotify.New(httpClient)
+	l.spotifox.connect = connect.NewController(l.spotifox.spotifyClient)
 
 	// get user profile
 	u, err := l.spotifox.spotifyClient.CurrentUser(context.Background())
@@ -402,7 +441,12 @@ func (l *LoginPage) handleLoginSuccess() (model.Page, tea.Cmd) {
 	user.Birthdate = u.Birthdate
 	user.Country = u.Country
 
+	if l.spotifox.user == nil || l.spotifox.user.ID != user.ID {
+		l.spotifox.likedSongs.Load(user.ID)
+	}
 	l.spotifox.user = &user
+	l.spotifox.updateCacheNamespace()
+	l.spotifox.syncLikedSongs()
 
 	table := storage.NewTable()
 	_ = table.SetByKVModel(storage.User{}, user)
@@ -419,6 +463,12 @@ func (l *LoginPage) handleLoginSuccess() (model.Page, tea.Cmd) {
 			newPage = p
 		}
 	}
+	// the link passed by command line is kept until logged in
+	if l.spotifox.startupLink != "" {
+		if p := l.spotifox.openStartupLink(); p != nil {
+			newPage = p
+		}
+	}
 	return newPage, tea.Tick(time.Nanosecond, func(t time.Time) tea.Msg { return newPage.Msg })
 }
 
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/lyric"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+// newLyricsProviders builds the chain of lyrics providers in the configured order,
+// the results of remote providers are cached
+func newLyricsProviders() lyric.Chain {
+	var (
+		config = configs.ConfigRegistry
+		client = &http.Client{Timeout: types.AppHttpTimeout}
+		chain  lyric.Chain
+	)
+	if !config.Main.ShowLyric {
+		return nil
+	}
+	for _, name := range config.Main.LyricsProviders {
+		switch strings.TrimSpace(name) {
+		case types.LyricsProviderLocal:
+			dir := config.Main.LyricsDir
+			if dir == "" {
+				dir = filepath.Join(utils.GetLocalDataDir(), types.LyricsDir)
+			}
+			chain = append(chain, &lyric.LocalProvider{Dir: dir})
+		case types.LyricsProviderSpotify:
+			if config.Spotify.Cookie != "" {
+				chain = append(chain, cachedLyricsProvider{lyric.NewSpotifyProvider(config.Spotify.Cookie)})
+			}
+		case types.LyricsProviderLRCLIB:
+			chain = append(chain, cachedLyricsProvider{lyric.NewHTTPProvider(types.LyricsProviderLRCLIB, lyric.LRCLIBURLTemplate, client)})
+		case types.LyricsProviderHTTP:
+			if config.Main.LyricsHttpUrl != "" {
+				chain = append(chain, cachedLyricsProvider{lyric.NewHTTPProvider(types.LyricsProviderHTTP, config.Main.LyricsHttpUrl, client)})
+			}
+		default:
+			utils.Logger().Printf("[WARN] unknown lyrics provider: %s", name)
+		}
+	}
+	return chain
+}
+
+// cachedLyricsProvider caches the results of provider, including not found
+type cachedLyricsProvider struct {
+	lyric.LyricsProvider
+}
+
+func (p cachedLyricsProvider) Lyrics(ctx context.Context, track lyric.Track) (*lyric.Result, error) {
+	cache := storage.LyricsCache{Provider: p.Name(), TrackId: track.Id}
+	if hp, ok := p.LyricsProvider.(*lyric.HTTPProvider); ok {
+		cache.Source = hp.URLTemplate
+	}
+	if cache.Load() {
+		if cache.NotFound {
+			return nil, lyric.ErrNotFound
+		}
+		return &lyric.Result{Provider: p.Name(), Synced: cache.Synced, Lyrics: cache.Lyrics, Translation: cache.Translation}, nil
+	}
+
+	res, err := p.LyricsProvider.Lyrics(ctx, track)
+	switch {
+	case errors.Is(err, lyric.ErrNotFound):
+		cache.NotFound = true
+		cache.Store()
+	case err == nil:
+		cache.Synced, cache.Lyrics, cache.Translation = res.Synced, res.Lyrics, res.Translation
+		cache.Store()
+	}
+	return res, err
+}
+
+func lyricTrackOf(song spotify.FullTrack) lyric.Track {
+	track := lyric.Track{
+		Id:       string(song.ID),
+		ISRC:     song.ExternalIDs["isrc"],
+		Title:    song.Name,
+		Album:    song.Album.Name,
+		Duration: time.Duration(song.Duration) * time.Millisecond,
+	}
+	for _, artist := range song.Artists {
+		track.Artists = append(track.Artists, artist.Name)
+	}
+	return track
+}
+
+// FetchSongLyrics asks the lyrics providers in order, nil if no lyrics found
+func (s *Spotifox) FetchSongLyrics(song spotify.FullTrack) (*lyric.LRCFile, *lyric.TranslateLRCFile) {
+	if len(s.lyricsProviders) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), types.AppHttpTimeout)
+	defer cancel()
+	track := lyricTrackOf(song)
+	res, err := s.lyricsProviders.Lyrics(ctx, track)
+	if err != nil {
+		if !errors.Is(err, lyric.ErrNotFound) {
+			utils.Logger().Printf("get song lyrics failed: %+v", err)
+		}
+		return nil, nil
+	}
+	lrcFile, transFile, err := res.File(track.Duration)
+	if err != nil {
+		utils.Logger().Printf("parse lyrics of %s failed: %+v", res.Provider, err)
+		return nil, nil
+	}
+	return lrcFile, transFile
+}
+
+// karaokeLine the current line of enhanced lrc, whose words are highlighted progressively
+type karaokeLine struct {
+	words  []lyric.LRCWord
+	endMs  int64  // start time of next line
+	suffix string // e.g. the translation
+}
+
+// split splits the line into the sung and unsung part at time t,
+// the word being sung is split in proportion to its elapsed time
+func (k *karaokeLine) split(t time.Duration) (sung, unsung string) {
+	var (
+		ms      = t.Milliseconds()
+		builder strings.Builder
+	)
+	for i, word := range k.words {
+		if ms < word.StartTimeMs {
+			return builder.String(), joinWords(k.words[i:])
+		}
+		end := k.endMs
+		if i+1 < len(k.words) {
+			end = k.words[i+1].StartTimeMs
+		}
+		if ms >= end || end <= word.StartTimeMs {
+			builder.WriteString(word.Content)
+			continue
+		}
+		runes := []rune(word.Content)
+		n := int(float64(len(runes)) * float64(ms-word.StartTimeMs) / float64(end-word.StartTimeMs))
+		builder.WriteString(string(runes[:n]))
+		return builder.String(), string(runes[n:]) + joinWords(k.words[i+1:])
+	}
+	return builder.String(), ""
+}
+
+func joinWords(words []lyric.LRCWord) string {
+	var builder strings.Builder
+	for _, word := range words {
+		builder.WriteString(word.Content)
+	}
+	return builder.String()
+}
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/anhoder/foxful-cli/util"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-musicfox/spotifox/internal/configs"
+	playerpkg "github.com/go-musicfox/spotifox/internal/player"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+const PageTypeLyrics model.PageType = "lyrics"
+
+type tickLyricsMsg struct{}
+
+func tickLyrics(duration time.Duration) tea.Cmd {
+	return tea.Tick(duration, func(t time.Time) tea.Msg {
+		return tickLyricsMsg{}
+	})
+}
+
+// LyricsPage the whole lyrics of playing song, the current line is kept in the middle.
+// The selected line follows playback again after a while since scrolled manually
+type LyricsPage struct {
+	spotifox  *Spotifox
+	menuTitle *model.MenuItem
+
+	selected   int
+	scrolledAt time.Time
+
+	// located by the last rendering, for mouse click
+	linesTop   int
+	firstIndex int
+	lineCount  int
+}
+
+func NewLyricsPage(spotifox *Spotifox) *LyricsPage {
+	return &LyricsPage{
+		spotifox:  spotifox,
+		menuTitle: &model.MenuItem{Title: locale.MustT("lyrics")},
+	}
+}
+
+func (p *LyricsPage) IgnoreQuitKeyMsg(_ tea.KeyMsg) bool {
+	return false
+}
+
+func (p *LyricsPage) Type() model.PageType {
+	return PageTypeLyrics
+}
+
+// following whether the selected line follows playback
+func (p *LyricsPage) following() bool {
+	return time.Since(p.scrolledAt) >= types.LyricsPageSnapBackDuration
+}
+
+// scroll moves the selected line manually
+func (p *LyricsPage) scroll(delta int) {
+	timer := p.spotifox.player.lrcTimer
+	if timer == nil || timer.IsEmpty() {
+		return
+	}
+	if p.following() {
+		p.selected = timer.CurIndex()
+	}
+	p.scrollTo(p.selected + delta)
+}
+
+func (p *LyricsPage) scrollTo(index int) {
+	timer := p.spotifox.player.lrcTimer
+	if timer == nil || timer.IsEmpty() {
+		return
+	}
+	p.selected = max(0, min(index, timer.Len()-1))
+	p.scrolledAt = time.Now()
+}
+
+// seekTo seeks to the start of line, which is followed immediately
+func (p *LyricsPage) seekTo(index int) {
+	player := p.spotifox.player
+	if player.lrcTimer == nil {
+		return
+	}
+	f, _ := player.lrcTimer.GetLRCFragment(index)
+	if f == nil {
+		return
+	}
+	player.Seek(max(time.Duration(f.StartTimeMs)*time.Millisecond-player.lyricOffset(), 0))
+	if player.State() != playerpkg.Playing {
+		player.Resume()
+	}
+	p.scrolledAt = time.Time{}
+}
+
+func (p *LyricsPage) Update(msg tea.Msg, a *model.App) (model.Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickLyricsMsg:
+		return p, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "y", "Y":
+			p.scrolledAt = time.Time{}
+			return p.spotifox.MustMain(), p.spotifox.RerenderCmd(true)
+		case "j", "down":
+			p.scroll(1)
+		case "k", "up":
+			p.scroll(-1)
+		case "g":
+			p.scrollTo(0)
+		case "G":
+			p.scrollTo(math.MaxInt)
+		case "{", "「":
+			p.spotifox.player.AdjustLyricOffset(-types.LyricOffsetStep)
+		case "}", "」":
+			p.spotifox.player.AdjustLyricOffset(types.LyricOffsetStep)
+		case "enter":
+			if !p.following() {
+				p.seekTo(p.selected)
+			}
+		}
+	case tea.MouseMsg:
+		switch {
+		case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+			if row := msg.Y - p.linesTop; row >= 0 && row < p.lineCount {
+				p.seekTo(p.firstIndex + row)
+			}
+		case msg.Button == tea.MouseButtonWheelDown && msg.Action == tea.MouseActionPress:
+			p.scroll(1)
+		case msg.Button == tea.MouseButtonWheelUp && msg.Action == tea.MouseActionPress:
+			p.scroll(-1)
+		}
+	}
+	return p, a.Tick(time.Nanosecond)
+}
+
+func (p *LyricsPage) View(a *model.App) string {
+	p.spotifox.applyUITasks()
+	var (
+		builder strings.Builder
+		top     int
+		main    = p.spotifox.MustMain()
+		player  = p.spotifox.player
+		timer   = player.lrcTimer
+	)
+
+	// title
+	if configs.ConfigRegistry.Main.ShowTitle {
+		builder.WriteString(main.TitleView(a, &top))
+	} else {
+		top++
+	}
+
+	// menu title
+	p.menuTitle.Subtitle = player.curSong.Name
+	if offsetTip := player.lyricOffsetTip(); offsetTip != "" {
+		p.menuTitle.Subtitle += " [" + offsetTip + "]"
+	}
+	builder.WriteString(main.MenuTitleView(a, &top, p.menuTitle))
+	builder.WriteString("\n\n")
+	top++
+
+	var (
+		startCol = main.MenuStartColumn()
+		maxLen   = a.WindowWidth() - startCol - 4
+		rows     = a.WindowHeight() - top - 3
+	)
+	p.linesTop, p.firstIndex, p.lineCount = top, 0, 0
+	if timer == nil || timer.IsEmpty() || rows <= 0 || maxLen <= 0 {
+		if startCol > 0 {
+			builder.WriteString(strings.Repeat(" ", startCol))
+		}
+		builder.WriteString(util.SetFgStyle(locale.MustT("no_lyrics"), termenv.ANSIBrightBlack))
+		builder.WriteString("\n")
+		top++
+	} else {
+		cur := timer.CurIndex()
+		if p.following() {
+			p.selected = cur
+		}
+		p.firstIndex = max(0, min(p.selected-rows/2, timer.Len()-rows))
+		p.lineCount = min(rows, timer.Len()-p.firstIndex)
+		for i := p.firstIndex; i < p.firstIndex+p.lineCount; i++ {
+			if startCol > 0 {
+				builder.WriteString(strings.Repeat(" ", startCol))
+			}
+			builder.WriteString(p.lineView(i, cur, maxLen))
+			builder.WriteString("\n")
+			top++
+		}
+	}
+
+	if a.WindowHeight() > top+2 {
+		builder.WriteString(strings.Repeat("\n", a.WindowHeight()-top-2))
+	}
+	builder.WriteString(player.progressView())
+	return builder.String()
+}
+
+func (p *LyricsPage) lineView(index, cur, maxLen int) string {
+	player := p.spotifox.player
+	if index == cur {
+		// the selected line is marked by the prompt when scrolled manually
+		if !p.following() && index == p.selected {
+			return util.SetFgStyle("> ", util.GetPrimaryColor()) + player.curLyricLineView(maxLen-2)
+		}
+		return player.curLyricLineView(maxLen)
+	}
+
+	f, tf := player.lrcTimer.GetLRCFragment(index)
+	if f == nil {
+		return ""
+	}
+	line := f.Content
+	if tf != nil && tf.Content != "" {
+		line += " [" + tf.Content + "]"
+	}
+	if !p.following() && index == p.selected {
+		line = runewidth.Truncate(runewidth.FillRight(line, maxLen-2), maxLen-2, "")
+		return util.SetFgStyle("> "+line, util.GetPrimaryColor())
+	}
+	return util.SetFgStyle(runewidth.Truncate(runewidth.FillRight(line, maxLen), maxLen, ""), termenv.ANSIBrightBlack)
+}
+
+func (p *LyricsPage) Msg() tea.Msg {
+	return tickLyricsMsg{}
+}
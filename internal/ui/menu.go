@@ -3,6 +3,7 @@ package ui
 import (
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
 	"github.com/zmb3/spotify/v2"
 )
 
@@ -20,6 +21,14 @@ type SongsMenu interface {
 	Songs() []spotify.FullTrack
 }
 
+// FullLoadableMenu songs menu with pagination, whose remaining songs can be loaded in background
+type FullLoadableMenu interface {
+	SongsMenu
+	// LoadAllSongs returns false if all songs are loaded or loading is in progress,
+	// onComplete is called on the UI goroutine, with nil if loading failed or was canceled
+	LoadAllSongs(onProgress func(loaded, total int), onComplete func(songs []spotify.FullTrack)) bool
+}
+
 type PlaylistsMenu interface {
 	Menu
 	Playlists() []spotify.SimplePlaylist
@@ -54,8 +63,44 @@ func (e *baseMenu) IsLocatable() bool {
 	return true
 }
 
+// BeforeBackMenuHook cancels the requests of the menu left
+func (e *baseMenu) BeforeBackMenuHook() model.Hook {
+	return e.backMenuHook(nil)
+}
+
+// backMenuHook runs hook before going back, and cancels the requests of the menu left if not stopped by hook.
+// The menus overriding BeforeBackMenuHook must wrap their hooks with it
+func (e *baseMenu) backMenuHook(hook model.Hook) model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		menu := main.CurMenu()
+		var page model.Page
+		if hook != nil {
+			var ok bool
+			if ok, page = hook(main); !ok {
+				return false, page
+			}
+		}
+		e.spotifox.cancelRequests(menu)
+		return true, page
+	}
+}
+
 func (e *baseMenu) handleFetchErr(err error) (bool, model.Page) {
+	var tips string
+	switch utils.CheckSpotifyErr(err) {
+	case utils.Canceled:
+		// menu was left
+		return false, nil
+	case utils.RateLimited:
+		tips = locale.MustT("api_rate_limited")
+	case utils.ServerError:
+		tips = locale.MustT("api_server_error")
+	case utils.NetworkError:
+		tips = locale.MustT("api_network_error")
+	default:
+		tips = "Err:" + err.Error()
+	}
 	utils.Logger().Printf("[ERROR] err: %+v", err)
-	model.NewMenuTips(e.spotifox.MustMain(), nil).DisplayTips("Err:" + err.Error())
+	model.NewMenuTips(e.spotifox.MustMain(), nil).DisplayTips(tips)
 	return false, nil
 }
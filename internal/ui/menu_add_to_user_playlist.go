@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"context"
-
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
@@ -61,7 +59,7 @@ func (m *AddToUserPlaylistMenu) BeforeEnterMenuHook() model.Hook {
 			res *spotify.SimplePlaylistPage
 			err error
 		)
-		res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(context.Background(), spotify.Limit(m.limit))
+		res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -107,7 +105,7 @@ func (m *AddToUserPlaylistMenu) BottomOutHook() model.Hook {
 			res *spotify.SimplePlaylistPage
 			err error
 		)
-		res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -14,13 +14,28 @@ type AlbumDetailMenu struct {
 	menus []model.MenuItem
 	songs []spotify.FullTrack
 	album spotify.SimpleAlbum
+
+	limit  int
+	offset int
+	total  int
+	loader *songsLoader
 }
 
 func NewAlbumDetailMenu(base baseMenu, album spotify.SimpleAlbum) *AlbumDetailMenu {
-	return &AlbumDetailMenu{
+	m := &AlbumDetailMenu{
 		baseMenu: base,
 		album:    album,
+
+		limit: 50,
 	}
+	m.loader = newSongsLoader(m.limit, base.spotifox.runOnUI, func(ctx context.Context, offset, limit int) ([]spotify.FullTrack, error) {
+		res, err := m.spotifox.spotifyClient.GetAlbumTracks(ctx, m.album.ID, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return nil, err
+		}
+		return m.songsOf(res), nil
+	})
+	return m
 }
 
 func (m *AlbumDetailMenu) IsSearchable() bool {
@@ -36,7 +51,7 @@ func (m *AlbumDetailMenu) GetMenuKey() string {
 }
 
 func (m *AlbumDetailMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *AlbumDetailMenu) BeforeEnterMenuHook() model.Hook {
@@ -45,29 +60,79 @@ func (m *AlbumDetailMenu) BeforeEnterMenuHook() model.Hook {
 			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
 			return false, page
 		}
-
-		res, err := m.spotifox.spotifyClient.GetAlbumTracks(context.Background(), m.album.ID, spotify.Limit(50))
+		// the songs loaded partially are dropped
+		m.loader.Cancel()
+		m.songs, m.menus, m.offset, m.total = nil, nil, 0, 0
+		res, err := m.spotifox.spotifyClient.GetAlbumTracks(m.spotifox.requestContext(m), m.album.ID, spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
 		if err != nil {
 			return m.handleFetchErr(errors.Wrap(err, "get album's songs failed"))
 		}
+		m.total = res.Total
+
+		m.songs = m.songsOf(res)
+		m.menus = utils.MenuItemsFromSongs(m.songs)
+
+		return true, nil
+	}
+}
 
-		var songs []spotify.FullTrack
-		for _, song := range res.Tracks {
-			songs = append(songs, spotify.FullTrack{
-				Album:       m.album,
-				SimpleTrack: song,
-			})
+func (m *AlbumDetailMenu) BottomOutHook() model.Hook {
+	if m.total <= m.limit+m.offset {
+		return nil
+	}
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(BottomOutHookCallback(main, m))
+			return false, page
 		}
-		m.songs = songs
+
+		m.offset += m.limit
+		res, err := m.spotifox.spotifyClient.GetAlbumTracks(m.spotifox.requestContext(m), m.album.ID, spotify.Limit(m.limit), spotify.Offset(m.offset))
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get album's songs failed"))
+		}
+		m.songs = append(m.songs, m.songsOf(res)...)
 		m.menus = utils.MenuItemsFromSongs(m.songs)
 
 		return true, nil
 	}
 }
 
+// songsOf the songs of album tracks page, which don't contain the album
+func (m *AlbumDetailMenu) songsOf(res *spotify.SimpleTrackPage) []spotify.FullTrack {
+	var songs []spotify.FullTrack
+	for _, song := range res.Tracks {
+		songs = append(songs, spotify.FullTrack{
+			Album:       m.album,
+			SimpleTrack: song,
+		})
+	}
+	return songs
+}
+
 func (m *AlbumDetailMenu) Songs() []spotify.FullTrack {
 	return m.songs
 }
+
+func (m *AlbumDetailMenu) LoadAllSongs(onProgress func(loaded, total int), onComplete func(songs []spotify.FullTrack)) bool {
+	if m.total <= m.limit+m.offset {
+		return false
+	}
+	return m.loader.Load(m.songs, m.limit+m.offset, m.total, onProgress, func(songs []spotify.FullTrack) {
+		if songs == nil {
+			onComplete(nil)
+			return
+		}
+		m.songs = songs
+		m.menus = utils.MenuItemsFromSongs(m.songs)
+		// all pages are loaded, disable BottomOutHook
+		m.offset = m.total
+		onComplete(songs)
+	})
+}
@@ -1,7 +1,7 @@
 package ui
 
 import (
-	"context"
+	"fmt"
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
@@ -9,23 +9,23 @@ import (
 	"github.com/zmb3/spotify/v2"
 )
 
-const AllType spotify.AlbumType = spotify.AlbumTypeAlbum | spotify.AlbumTypeSingle | spotify.AlbumTypeAppearsOn | spotify.AlbumTypeCompilation
-
 type ArtistAlbumMenu struct {
 	baseMenu
-	menus    []model.MenuItem
-	albums   []spotify.SimpleAlbum
-	artistId spotify.ID
-	limit    int
-	offset   int
-	total    int
+	menus     []model.MenuItem
+	albums    []spotify.SimpleAlbum
+	artistId  spotify.ID
+	albumType spotify.AlbumType
+	limit     int
+	offset    int
+	total     int
 }
 
-func NewArtistAlbumMenu(base baseMenu, artistId spotify.ID) *ArtistAlbumMenu {
+func NewArtistAlbumMenu(base baseMenu, artistId spotify.ID, albumType spotify.AlbumType) *ArtistAlbumMenu {
 	return &ArtistAlbumMenu{
-		baseMenu: base,
-		artistId: artistId,
-		limit:    50,
+		baseMenu:  base,
+		artistId:  artistId,
+		albumType: albumType,
+		limit:     50,
 	}
 }
 
@@ -34,7 +34,7 @@ func (m *ArtistAlbumMenu) IsSearchable() bool {
 }
 
 func (m *ArtistAlbumMenu) GetMenuKey() string {
-	return "artist_album_" + string(m.artistId)
+	return fmt.Sprintf("artist_album_%d_%s", m.albumType, m.artistId)
 }
 
 func (m *ArtistAlbumMenu) MenuViews() []model.MenuItem {
@@ -56,7 +56,7 @@ func (m *ArtistAlbumMenu) BeforeEnterMenuHook() model.Hook {
 			return false, page
 		}
 
-		res, err := m.spotifox.spotifyClient.GetArtistAlbums(context.Background(), m.artistId, []spotify.AlbumType{AllType}, spotify.Limit(m.limit))
+		res, err := m.spotifox.spotifyClient.GetArtistAlbums(m.spotifox.requestContext(m), m.artistId, []spotify.AlbumType{m.albumType}, spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -87,7 +87,7 @@ func (m *ArtistAlbumMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		res, err := m.spotifox.spotifyClient.GetArtistAlbums(context.Background(), m.artistId, []spotify.AlbumType{AllType}, spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err := m.spotifox.spotifyClient.GetArtistAlbums(m.spotifox.requestContext(m), m.artistId, []spotify.AlbumType{m.albumType}, spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
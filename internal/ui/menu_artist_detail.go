@@ -1,28 +1,40 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
 	"github.com/zmb3/spotify/v2"
 )
 
+const (
+	artistDetailTopTracks = iota
+	artistDetailAlbums
+	artistDetailSingles
+	artistDetailCompilations
+	artistDetailAppearsOn
+	artistDetailRelated
+	artistDetailFollow
+)
+
 type ArtistDetailMenu struct {
 	baseMenu
-	menus    []model.MenuItem
-	artistId spotify.ID
+	artistId   spotify.ID
+	artistName string
+	artist     *spotify.FullArtist
+	followed   bool
 }
 
 func NewArtistDetailMenu(base baseMenu, artistId spotify.ID, artistName string) *ArtistDetailMenu {
-	artistMenu := &ArtistDetailMenu{
-		baseMenu: base,
-		menus: []model.MenuItem{
-			{Title: locale.MustT("artist_top_track"), Subtitle: artistName},
-			{Title: locale.MustT("artist_album"), Subtitle: artistName},
-		},
-		artistId: artistId,
+	return &ArtistDetailMenu{
+		baseMenu:   base,
+		artistId:   artistId,
+		artistName: artistName,
 	}
-
-	return artistMenu
 }
 
 func (m *ArtistDetailMenu) GetMenuKey() string {
@@ -30,16 +42,112 @@ func (m *ArtistDetailMenu) GetMenuKey() string {
 }
 
 func (m *ArtistDetailMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	followTitle := locale.MustT("follow_artist")
+	if m.followed {
+		followTitle = locale.MustT("unfollow_artist")
+	}
+	return []model.MenuItem{
+		{Title: locale.MustT("artist_top_track"), Subtitle: m.artistName},
+		{Title: locale.MustT("artist_album"), Subtitle: m.artistName},
+		{Title: locale.MustT("artist_single"), Subtitle: m.artistName},
+		{Title: locale.MustT("artist_compilation"), Subtitle: m.artistName},
+		{Title: locale.MustT("artist_appears_on"), Subtitle: m.artistName},
+		{Title: locale.MustT("related_artists"), Subtitle: m.artistName},
+		{Title: followTitle, Subtitle: m.artistName},
+	}
 }
 
-func (m *ArtistDetailMenu) SubMenu(_ *model.App, index int) model.Menu {
+func (m *ArtistDetailMenu) FormatMenuItem(item *model.MenuItem) {
+	if m.artist == nil {
+		return
+	}
+	var header []string
+	if len(m.artist.Genres) > 0 {
+		header = append(header, "["+strings.Join(m.artist.Genres, ", ")+"]")
+	}
+	header = append(header, "["+locale.MustT("artist_followers", locale.WithTplData(map[string]string{"Count": utils.FormatCount(m.artist.Followers.Count)}))+"]")
+	item.Subtitle = strings.Join(header, " ")
+}
+
+func (m *ArtistDetailMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+
+		artist, err := m.spotifox.spotifyClient.GetArtist(m.spotifox.requestContext(m), m.artistId)
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get artist failed"))
+		}
+		m.artist = artist
+		if m.artistName == "" {
+			m.artistName = artist.Name
+		}
+
+		followed, err := m.spotifox.spotifyClient.CurrentUserFollows(m.spotifox.requestContext(m), "artist", m.artistId)
+		if err != nil {
+			utils.Logger().Printf("check followed artist failed: %+v", err)
+		} else if len(followed) > 0 {
+			m.followed = followed[0]
+		}
+
+		return true, nil
+	}
+}
+
+func (m *ArtistDetailMenu) SubMenu(app *model.App, index int) model.Menu {
 	switch index {
-	case 0:
+	case artistDetailTopTracks:
 		return NewArtistSongMenu(m.baseMenu, m.artistId)
-	case 1:
-		return NewArtistAlbumMenu(m.baseMenu, m.artistId)
+	case artistDetailAlbums:
+		return NewArtistAlbumMenu(m.baseMenu, m.artistId, spotify.AlbumTypeAlbum)
+	case artistDetailSingles:
+		return NewArtistAlbumMenu(m.baseMenu, m.artistId, spotify.AlbumTypeSingle)
+	case artistDetailCompilations:
+		return NewArtistAlbumMenu(m.baseMenu, m.artistId, spotify.AlbumTypeCompilation)
+	case artistDetailAppearsOn:
+		return NewArtistAlbumMenu(m.baseMenu, m.artistId, spotify.AlbumTypeAppearsOn)
+	case artistDetailRelated:
+		return NewRelatedArtistMenu(m.baseMenu, m.artistId)
+	case artistDetailFollow:
+		m.toggleFollow(app.MustMain())
 	}
 
 	return nil
 }
+
+func (m *ArtistDetailMenu) toggleFollow(main *model.Main) {
+	loading := model.NewLoading(main)
+	loading.Start()
+	defer loading.Complete()
+
+	followOrNot := !m.followed
+	if !m.spotifox.FollowArtist(m.artistId, followOrNot) {
+		return
+	}
+	m.followed = followOrNot
+	if m.artist != nil {
+		if followOrNot {
+			m.artist.Followers.Count++
+		} else if m.artist.Followers.Count > 0 {
+			m.artist.Followers.Count--
+		}
+	}
+	main.RefreshMenuList()
+	main.RefreshMenuTitle()
+
+	var title = locale.MustT("follow_artist_success")
+	if !followOrNot {
+		title = locale.MustT("unfollow_artist_success")
+	}
+	utils.Notify(utils.NotifyContent{
+		Title:   title,
+		Text:    m.artistName,
+		Url:     utils.WebURLOfArtist(m.artistId),
+		GroupId: types.GroupID,
+	})
+}
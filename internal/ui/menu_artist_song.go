@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"context"
-
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
@@ -36,7 +34,7 @@ func (m *ArtistSongMenu) GetMenuKey() string {
 }
 
 func (m *ArtistSongMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *ArtistSongMenu) BeforeEnterMenuHook() model.Hook {
@@ -50,7 +48,7 @@ func (m *ArtistSongMenu) BeforeEnterMenuHook() model.Hook {
 		if m.spotifox.user.Country != "" {
 			country = m.spotifox.user.Country
 		}
-		res, err := m.spotifox.spotifyClient.GetArtistsTopTracks(context.Background(), m.artistId, country)
+		res, err := m.spotifox.spotifyClient.GetArtistsTopTracks(m.spotifox.requestContext(m), m.artistId, country)
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/player"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+// DevicesMenu lists Spotify Connect devices, the first item is spotifox itself
+type DevicesMenu struct {
+	baseMenu
+	devices []spotify.PlayerDevice
+}
+
+func NewDevicesMenu(base baseMenu) *DevicesMenu {
+	return &DevicesMenu{
+		baseMenu: base,
+	}
+}
+
+func (m *DevicesMenu) GetMenuKey() string {
+	return "devices"
+}
+
+func (m *DevicesMenu) MenuViews() []model.MenuItem {
+	var (
+		remoteDevice, _, _, inRemote = m.spotifox.player.RemoteSnapshot()
+		active                       = "[" + locale.MustT("device_active") + "]"
+	)
+
+	local := model.MenuItem{Title: locale.MustT("this_device", locale.WithTplData(map[string]string{"AppName": types.AppName}))}
+	if !inRemote {
+		local.Subtitle = active
+	}
+	menus := []model.MenuItem{local}
+
+	for _, device := range m.devices {
+		var subtitle []string
+		if device.Type != "" {
+			subtitle = append(subtitle, "["+device.Type+"]")
+		}
+		if inRemote && device.ID == remoteDevice.ID {
+			subtitle = append(subtitle, active)
+		}
+		if device.Restricted {
+			subtitle = append(subtitle, "["+locale.MustT("device_restricted")+"]")
+		} else {
+			subtitle = append(subtitle, fmt.Sprintf("[%d%%]", device.Volume))
+		}
+		menus = append(menus, model.MenuItem{Title: utils.ReplaceSpecialStr(device.Name), Subtitle: strings.Join(subtitle, " ")})
+	}
+	return menus
+}
+
+func (m *DevicesMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+
+		devices, err := m.spotifox.connect.Devices(m.spotifox.requestContext(m))
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get devices failed"))
+		}
+		m.devices = devices
+
+		// another device is playing, act as its remote
+		p := m.spotifox.player
+		if !p.InRemoteMode() && p.State() != player.Playing {
+			for _, device := range devices {
+				if device.Active && !device.Restricted {
+					p.EnterRemoteMode(device)
+					break
+				}
+			}
+		}
+
+		return true, nil
+	}
+}
+
+func (m *DevicesMenu) SubMenu(app *model.App, index int) model.Menu {
+	if index == 0 {
+		m.pullBack(app.MustMain())
+		return nil
+	}
+	if index-1 < len(m.devices) {
+		m.transferTo(app.MustMain(), m.devices[index-1])
+	}
+	return nil
+}
+
+// transferTo moves the playback of spotifox or the followed device to the device
+func (m *DevicesMenu) transferTo(main *model.Main, device spotify.PlayerDevice) {
+	if device.Restricted {
+		model.NewMenuTips(main, nil).DisplayTips(locale.MustT("device_restricted"))
+		return
+	}
+
+	loading := model.NewLoading(main)
+	loading.Start()
+	defer loading.Complete()
+
+	var (
+		p        = m.spotifox.player
+		trackId  spotify.ID
+		position = p.PassedTime()
+	)
+	if !p.InRemoteMode() && p.curSongIndex < len(p.playlist) {
+		trackId = p.curSong.ID
+	}
+	if err := m.spotifox.connect.TransferTo(m.spotifox.requestContext(m), device.ID, trackId, position); err != nil {
+		utils.Logger().Printf("transfer playback failed: %+v", err)
+		model.NewMenuTips(main, nil).DisplayTips("Err:" + err.Error())
+		return
+	}
+	p.EnterRemoteMode(device)
+	main.RefreshMenuList()
+
+	text := p.curSong.Name
+	if _, state, _, _ := p.RemoteSnapshot(); state != nil && state.Item != nil {
+		text = state.Item.Name
+	}
+	utils.Notify(utils.NotifyContent{
+		Title:   locale.MustT("transfer_playback_success", locale.WithTplData(map[string]string{"DeviceName": device.Name})),
+		Text:    text,
+		GroupId: types.GroupID,
+	})
+}
+
+// pullBack pauses the followed device and continues its track on spotifox
+func (m *DevicesMenu) pullBack(main *model.Main) {
+	loading := model.NewLoading(main)
+	loading.Start()
+	defer loading.Complete()
+
+	p := m.spotifox.player
+	state, err := m.spotifox.connect.Takeover(m.spotifox.requestContext(m))
+	if err != nil {
+		utils.Logger().Printf("take over playback failed: %+v", err)
+		model.NewMenuTips(main, nil).DisplayTips("Err:" + err.Error())
+		return
+	}
+	p.ExitRemoteMode()
+	main.RefreshMenuList()
+	if state == nil || state.Item == nil {
+		return
+	}
+
+	song := *state.Item
+	index := -1
+	for i := range p.playlist {
+		if p.playlist[i].ID == song.ID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		p.playlist = append(p.playlist, song)
+		index = len(p.playlist) - 1
+	}
+	p.curSongIndex = index
+	p.pendingSeek = time.Duration(state.Progress) * time.Millisecond
+	p.PlaySong(song, DurationNext)
+}
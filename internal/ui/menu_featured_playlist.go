@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"context"
-
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
@@ -55,7 +53,7 @@ func (m *FeaturedPlaylistMenu) BeforeEnterMenuHook() model.Hook {
 			return false, page
 		}
 
-		msg, res, err := m.spotifox.spotifyClient.FeaturedPlaylists(context.Background(), spotify.Limit(m.limit))
+		msg, res, err := m.spotifox.spotifyClient.FeaturedPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -85,7 +83,7 @@ func (m *FeaturedPlaylistMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		_, res, err := m.spotifox.spotifyClient.FeaturedPlaylists(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		_, res, err := m.spotifox.spotifyClient.FeaturedPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
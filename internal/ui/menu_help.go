@@ -56,6 +56,15 @@ func NewHelpMenu(base baseMenu) *HelpMenu {
 			{Title: "O", Subtitle: locale.MustT("open_selected_item_url")},
 			{Title: ";/:", Subtitle: locale.MustT("follow_selected_playlist")},
 			{Title: "'/\"", Subtitle: locale.MustT("unfollow_selected_playlist")},
+			{Title: "e", Subtitle: locale.MustT("save_album_of_playing_track")},
+			{Title: "E", Subtitle: locale.MustT("save_selected_album")},
+			{Title: "u", Subtitle: locale.MustT("unsave_album_of_playing_track")},
+			{Title: "U", Subtitle: locale.MustT("unsave_selected_album")},
+			{Title: "i", Subtitle: locale.MustT("track_info_of_playing_track")},
+			{Title: "I", Subtitle: locale.MustT("track_info_of_selected_track")},
+			{Title: "Ctrl+O", Subtitle: locale.MustT("open_link")},
+			{Title: "y/Y", Subtitle: locale.MustT("lyrics_page")},
+			{Title: "{/}", Subtitle: locale.MustT("adjust_lyric_offset")},
 		},
 	}
 
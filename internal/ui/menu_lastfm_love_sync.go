@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/lastfm"
+	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+// LastfmLoveSyncMenu likes the tracks loved on Last.fm, the ambiguous matches are listed for review.
+// Matching runs in background and is canceled when leaving the menu
+type LastfmLoveSyncMenu struct {
+	baseMenu
+
+	l        sync.Mutex
+	started  bool
+	running  bool
+	err      error
+	matched  int
+	total    int
+	reviews  []lastfm.LoveMatch
+	resolved map[int]bool
+
+	liked        int
+	alreadyLiked int
+	notFound     int
+}
+
+func NewLastfmLoveSyncMenu(base baseMenu) *LastfmLoveSyncMenu {
+	return &LastfmLoveSyncMenu{
+		baseMenu: base,
+		resolved: make(map[int]bool),
+	}
+}
+
+func (m *LastfmLoveSyncMenu) GetMenuKey() string {
+	return "lastfm_love_sync"
+}
+
+func (m *LastfmLoveSyncMenu) MenuViews() []model.MenuItem {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	var summary model.MenuItem
+	switch {
+	case m.err != nil:
+		summary.Title = locale.MustT("love_sync_failed")
+		summary.Subtitle = "[" + locale.MustT("error") + ": " + m.err.Error() + "]"
+	case m.running:
+		summary.Title = locale.MustT("love_sync_matching", locale.WithTplData(map[string]int{"Matched": m.matched, "Total": m.total}))
+	default:
+		summary.Title = locale.MustT("love_sync_summary", locale.WithTplData(map[string]int{
+			"Liked":        m.liked,
+			"AlreadyLiked": m.alreadyLiked,
+			"Review":       len(m.reviews),
+			"NotFound":     m.notFound,
+		}))
+	}
+
+	menus := []model.MenuItem{summary}
+	for i, match := range m.reviews {
+		subtitle := locale.MustT("love_sync_review_candidates", locale.WithTplData(map[string]int{"Count": len(match.Candidates)}))
+		if m.resolved[i] {
+			subtitle = "[" + locale.MustT("love_sync_liked") + "]"
+		}
+		menus = append(menus, model.MenuItem{
+			Title:    utils.ReplaceSpecialStr(match.Loved.Track),
+			Subtitle: utils.ReplaceSpecialStr(match.Loved.Artist) + " " + subtitle,
+		})
+	}
+	return menus
+}
+
+func (m *LastfmLoveSyncMenu) SubMenu(_ *model.App, index int) model.Menu {
+	m.l.Lock()
+	defer m.l.Unlock()
+	if index < 1 || index > len(m.reviews) {
+		return nil
+	}
+	return NewLastfmLoveReviewMenu(m.baseMenu, m, index-1)
+}
+
+func (m *LastfmLoveSyncMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+		if m.spotifox.lastfmUser == nil || !m.spotifox.lastfm.Authed() {
+			return false, nil
+		}
+
+		m.l.Lock()
+		defer m.l.Unlock()
+		if !m.started {
+			m.started, m.running = true, true
+			ctx, user := m.spotifox.requestContext(m), m.spotifox.lastfmUser.Name
+			go utils.PanicRecoverWrapper(false, func() {
+				m.reconcile(ctx, user)
+			})
+		}
+		return true, nil
+	}
+}
+
+// reconcile matches the loved tracks with search results of Spotify, likes the exact matches
+// and keeps the ambiguous ones for review
+func (m *LastfmLoveSyncMenu) reconcile(ctx context.Context, user string) {
+	err := m.matchAndLike(ctx, user)
+
+	m.l.Lock()
+	m.running = false
+	if err != nil && utils.CheckSpotifyErr(err) != utils.Canceled && !errors.Is(err, context.Canceled) {
+		utils.Logger().Printf("[ERROR] sync loved tracks failed: %+v", err)
+		m.err = err
+	}
+	m.l.Unlock()
+	m.refresh()
+}
+
+func (m *LastfmLoveSyncMenu) matchAndLike(ctx context.Context, user string) error {
+	loved, err := m.spotifox.lastfm.LovedTracks(ctx, user)
+	if err != nil {
+		return errors.Wrap(err, "get loved tracks failed")
+	}
+	m.l.Lock()
+	m.total = len(loved)
+	m.l.Unlock()
+	m.refresh()
+
+	var (
+		toLike []spotify.ID
+		opts   = []spotify.RequestOption{spotify.Limit(types.LastfmLoveSearchLimit)}
+	)
+	if m.spotifox.user != nil && m.spotifox.user.Country != "" {
+		opts = append(opts, spotify.Market(m.spotifox.user.Country))
+	}
+	for _, track := range loved {
+		res, err := m.spotifox.spotifyClient.Search(ctx, track.SearchQuery(), spotify.SearchTypeTrack, opts...)
+		if err != nil {
+			return errors.Wrap(err, "search loved track failed")
+		}
+
+		var results []spotify.FullTrack
+		if res.Tracks != nil {
+			results = res.Tracks.Tracks
+		}
+		match := lastfm.MatchLovedTrack(track, results)
+
+		m.l.Lock()
+		switch {
+		case match.Match != nil:
+			if liked, _ := m.spotifox.likedSongs.Liked(match.Match.ID); liked {
+				m.alreadyLiked++
+			} else {
+				toLike = append(toLike, match.Match.ID)
+			}
+		case match.Ambiguous():
+			m.reviews = append(m.reviews, match)
+		default:
+			m.notFound++
+		}
+		m.matched++
+		m.l.Unlock()
+		m.refresh()
+	}
+
+	for start := 0; start < len(toLike); start += likedSongsSyncPageSize {
+		ids := toLike[start:min(start+likedSongsSyncPageSize, len(toLike))]
+		if err := m.spotifox.spotifyClient.AddTracksToLibrary(ctx, ids...); err != nil {
+			return errors.Wrap(err, "like loved tracks failed")
+		}
+		for _, id := range ids {
+			m.spotifox.likedSongs.Set(id, true)
+		}
+		m.l.Lock()
+		m.liked += len(ids)
+		m.l.Unlock()
+	}
+	return nil
+}
+
+// refresh rerenders the menu if it's still displayed
+func (m *LastfmLoveSyncMenu) refresh() {
+	m.spotifox.runOnUI(func() {
+		if main := m.spotifox.MustMain(); main.CurMenu() == m {
+			main.RefreshMenuList()
+		}
+	})
+}
+
+// resolve marks the ambiguous match as liked
+func (m *LastfmLoveSyncMenu) resolve(index int) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.resolved[index] = true
+}
+
+// LastfmLoveReviewMenu candidates of an ambiguous loved track, the selected one is liked when entered
+type LastfmLoveReviewMenu struct {
+	baseMenu
+	sync       *LastfmLoveSyncMenu
+	index      int
+	candidates []spotify.FullTrack
+	menus      []model.MenuItem
+}
+
+func NewLastfmLoveReviewMenu(base baseMenu, sync *LastfmLoveSyncMenu, index int) *LastfmLoveReviewMenu {
+	candidates := sync.reviews[index].Candidates
+	return &LastfmLoveReviewMenu{
+		baseMenu:   base,
+		sync:       sync,
+		index:      index,
+		candidates: candidates,
+		menus:      utils.MenuItemsFromSongs(candidates),
+	}
+}
+
+func (m *LastfmLoveReviewMenu) IsPlayable() bool {
+	return true
+}
+
+func (m *LastfmLoveReviewMenu) GetMenuKey() string {
+	return "lastfm_love_review"
+}
+
+func (m *LastfmLoveReviewMenu) MenuViews() []model.MenuItem {
+	return m.spotifox.likedSongs.markLiked(m.menus, m.candidates)
+}
+
+func (m *LastfmLoveReviewMenu) SubMenu(app *model.App, index int) model.Menu {
+	if index >= len(m.candidates) {
+		return nil
+	}
+	main := app.MustMain()
+	loading := model.NewLoading(main)
+	loading.Start()
+	ok := m.spotifox.LikeSong(m.candidates[index].ID, true)
+	loading.Complete()
+	if !ok {
+		return nil
+	}
+
+	m.sync.resolve(m.index)
+	main.RefreshMenuList()
+	model.NewMenuTips(main, nil).DisplayTips(locale.MustT("love_sync_liked") + ": " + m.candidates[index].Name)
+	return nil
+}
+
+func (m *LastfmLoveReviewMenu) Songs() []spotify.FullTrack {
+	return m.candidates
+}
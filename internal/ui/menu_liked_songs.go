@@ -17,13 +17,26 @@ type LikedSongsMenu struct {
 	limit  int
 	offset int
 	total  int
+	loader *songsLoader
 }
 
 func NewLikedSongsMenu(base baseMenu) *LikedSongsMenu {
-	return &LikedSongsMenu{
+	m := &LikedSongsMenu{
 		baseMenu: base,
 		limit:    50,
 	}
+	m.loader = newSongsLoader(m.limit, base.spotifox.runOnUI, func(ctx context.Context, offset, limit int) ([]spotify.FullTrack, error) {
+		res, err := m.spotifox.spotifyClient.CurrentUsersTracks(ctx, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return nil, err
+		}
+		var songs []spotify.FullTrack
+		for i := range res.Tracks {
+			songs = append(songs, res.Tracks[i].FullTrack)
+		}
+		return songs, nil
+	})
+	return m
 }
 
 func (m *LikedSongsMenu) IsSearchable() bool {
@@ -39,7 +52,7 @@ func (m *LikedSongsMenu) GetMenuKey() string {
 }
 
 func (m *LikedSongsMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *LikedSongsMenu) SubMenu(_ *model.App, _ int) model.Menu {
@@ -52,7 +65,10 @@ func (m *LikedSongsMenu) BeforeEnterMenuHook() model.Hook {
 			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
 			return false, page
 		}
-		res, err := m.spotifox.spotifyClient.CurrentUsersTracks(context.Background(), spotify.Limit(m.limit))
+		// the songs loaded partially are dropped
+		m.loader.Cancel()
+		m.songs, m.menus, m.offset, m.total = nil, nil, 0, 0
+		res, err := m.spotifox.spotifyClient.CurrentUsersTracks(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -83,7 +99,7 @@ func (m *LikedSongsMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		res, err := m.spotifox.spotifyClient.CurrentUsersTracks(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err := m.spotifox.spotifyClient.CurrentUsersTracks(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -102,3 +118,20 @@ func (m *LikedSongsMenu) BottomOutHook() model.Hook {
 func (m *LikedSongsMenu) Songs() []spotify.FullTrack {
 	return m.songs
 }
+
+func (m *LikedSongsMenu) LoadAllSongs(onProgress func(loaded, total int), onComplete func(songs []spotify.FullTrack)) bool {
+	if m.total <= m.limit+m.offset {
+		return false
+	}
+	return m.loader.Load(m.songs, m.limit+m.offset, m.total, onProgress, func(songs []spotify.FullTrack) {
+		if songs == nil {
+			onComplete(nil)
+			return
+		}
+		m.songs = songs
+		m.menus = utils.MenuItemsFromSongs(m.songs)
+		// all pages are loaded, disable BottomOutHook
+		m.offset = m.total
+		onComplete(songs)
+	})
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
 )
 
@@ -9,6 +10,9 @@ type MainMenu struct {
 	baseMenu
 	menus    []model.MenuItem
 	menuList []Menu
+
+	// saved searches are inserted after search
+	savedSearchIndex int
 }
 
 func NewMainMenu(netease *Spotifox) *MainMenu {
@@ -19,10 +23,14 @@ func NewMainMenu(netease *Spotifox) *MainMenu {
 			{Title: locale.MustT("liked_tracks")},
 			{Title: locale.MustT("followed_playlists")},
 			{Title: locale.MustT("followed_artists")},
+			{Title: locale.MustT("saved_albums")},
 			{Title: locale.MustT("featured_playlist")},
 			// {Title: locale.MustT("my_top_tracks")},
 			{Title: locale.MustT("search")},
+			{Title: locale.MustT("devices")},
+			{Title: locale.MustT("open_link")},
 			{Title: "LastFM"},
+			{Title: locale.MustT("switch_account")},
 			{Title: locale.MustT("help")},
 			{Title: locale.MustT("check_update")},
 		},
@@ -30,14 +38,23 @@ func NewMainMenu(netease *Spotifox) *MainMenu {
 			NewLikedSongsMenu(base),
 			NewUserPlaylistMenu(base, CurUser),
 			NewUserArtistMenu(base),
+			NewSavedAlbumsMenu(base),
 			NewFeaturedPlaylistMenu(base),
 			// NewUserTopSongsMenu(base),
-			NewSearchTypeMenu(base),
+			NewSearchMenu(base),
+			NewDevicesMenu(base),
+			NewOpenLinkMenu(base),
 			NewLastfm(base),
+			NewSwitchAccountMenu(base),
 			NewHelpMenu(base),
 			NewCheckUpdateMenu(base),
 		},
 	}
+	for i, menu := range mainMenu.menuList {
+		if _, ok := menu.(*SearchMenu); ok {
+			mainMenu.savedSearchIndex = i + 1
+		}
+	}
 	return mainMenu
 }
 
@@ -61,13 +78,24 @@ func (m *MainMenu) MenuViews() []model.MenuItem {
 	for i, menu := range m.menuList {
 		menu.FormatMenuItem(&m.menus[i])
 	}
-	return m.menus
+
+	var menus []model.MenuItem
+	menus = append(menus, m.menus[:m.savedSearchIndex]...)
+	for _, saved := range m.spotifox.savedSearches {
+		menus = append(menus, model.MenuItem{Title: utils.ReplaceSpecialStr(saved.Name), Subtitle: "「" + saved.Query + "」"})
+	}
+	return append(menus, m.menus[m.savedSearchIndex:]...)
 }
 
 func (m *MainMenu) SubMenu(_ *model.App, index int) model.Menu {
-	if index >= len(m.menuList) {
-		return nil
+	saved := m.spotifox.savedSearches
+	switch {
+	case index < m.savedSearchIndex:
+		return m.menuList[index]
+	case index < m.savedSearchIndex+len(saved):
+		return NewSavedSearchMenu(m.baseMenu, saved[index-m.savedSearchIndex])
+	case index-len(saved) < len(m.menuList):
+		return m.menuList[index-len(saved)]
 	}
-
-	return m.menuList[index]
+	return nil
 }
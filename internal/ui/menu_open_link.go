@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"github.com/anhoder/foxful-cli/model"
+)
+
+// OpenLinkMenu entry of open link page
+type OpenLinkMenu struct {
+	baseMenu
+}
+
+func NewOpenLinkMenu(base baseMenu) *OpenLinkMenu {
+	return &OpenLinkMenu{
+		baseMenu: base,
+	}
+}
+
+func (m *OpenLinkMenu) GetMenuKey() string {
+	return "open_link"
+}
+
+func (m *OpenLinkMenu) MenuViews() []model.MenuItem {
+	return nil
+}
+
+func (m *OpenLinkMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		page, _ := m.spotifox.ToOpenLinkPage()
+		return false, page
+	}
+}
@@ -18,15 +18,31 @@ type PlaylistDetailMenu struct {
 	limit  int
 	offset int
 	total  int
+	loader *songsLoader
 }
 
 func NewPlaylistDetailMenu(base baseMenu, playlistId spotify.ID) *PlaylistDetailMenu {
-	return &PlaylistDetailMenu{
+	m := &PlaylistDetailMenu{
 		baseMenu:   base,
 		playlistId: playlistId,
 
 		limit: 50,
 	}
+	m.loader = newSongsLoader(m.limit, base.spotifox.runOnUI, func(ctx context.Context, offset, limit int) ([]spotify.FullTrack, error) {
+		res, err := m.spotifox.spotifyClient.GetPlaylistItems(ctx, m.playlistId, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return nil, err
+		}
+		var songs []spotify.FullTrack
+		for _, v := range res.Items {
+			if v.Track.Track == nil {
+				continue
+			}
+			songs = append(songs, *v.Track.Track)
+		}
+		return songs, nil
+	})
+	return m
 }
 
 func (m *PlaylistDetailMenu) IsSearchable() bool {
@@ -42,7 +58,7 @@ func (m *PlaylistDetailMenu) GetMenuKey() string {
 }
 
 func (m *PlaylistDetailMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *PlaylistDetailMenu) SubMenu(_ *model.App, _ int) model.Menu {
@@ -55,7 +71,10 @@ func (m *PlaylistDetailMenu) BeforeEnterMenuHook() model.Hook {
 			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
 			return false, page
 		}
-		res, err := m.spotifox.spotifyClient.GetPlaylistItems(context.Background(), m.playlistId, spotify.Limit(m.limit))
+		// the songs loaded partially are dropped
+		m.loader.Cancel()
+		m.songs, m.menus, m.offset, m.total = nil, nil, 0, 0
+		res, err := m.spotifox.spotifyClient.GetPlaylistItems(m.spotifox.requestContext(m), m.playlistId, spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -87,7 +106,7 @@ func (m *PlaylistDetailMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		res, err := m.spotifox.spotifyClient.GetPlaylistItems(context.Background(), m.playlistId, spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err := m.spotifox.spotifyClient.GetPlaylistItems(m.spotifox.requestContext(m), m.playlistId, spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -109,3 +128,20 @@ func (m *PlaylistDetailMenu) BottomOutHook() model.Hook {
 func (m *PlaylistDetailMenu) Songs() []spotify.FullTrack {
 	return m.songs
 }
+
+func (m *PlaylistDetailMenu) LoadAllSongs(onProgress func(loaded, total int), onComplete func(songs []spotify.FullTrack)) bool {
+	if m.total <= m.limit+m.offset {
+		return false
+	}
+	return m.loader.Load(m.songs, m.limit+m.offset, m.total, onProgress, func(songs []spotify.FullTrack) {
+		if songs == nil {
+			onComplete(nil)
+			return
+		}
+		m.songs = songs
+		m.menus = utils.MenuItemsFromSongs(m.songs)
+		// all pages are loaded, disable BottomOutHook
+		m.offset = m.total
+		onComplete(songs)
+	})
+}
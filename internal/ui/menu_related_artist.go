@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+type RelatedArtistMenu struct {
+	baseMenu
+	menus    []model.MenuItem
+	artists  []spotify.SimpleArtist
+	artistId spotify.ID
+}
+
+func NewRelatedArtistMenu(base baseMenu, artistId spotify.ID) *RelatedArtistMenu {
+	return &RelatedArtistMenu{
+		baseMenu: base,
+		artistId: artistId,
+	}
+}
+
+func (m *RelatedArtistMenu) IsSearchable() bool {
+	return true
+}
+
+func (m *RelatedArtistMenu) GetMenuKey() string {
+	return "related_artist_" + string(m.artistId)
+}
+
+func (m *RelatedArtistMenu) MenuViews() []model.MenuItem {
+	return m.menus
+}
+
+func (m *RelatedArtistMenu) Artists() []spotify.SimpleArtist {
+	return m.artists
+}
+
+func (m *RelatedArtistMenu) SubMenu(_ *model.App, index int) model.Menu {
+	if index >= len(m.artists) {
+		return nil
+	}
+	return NewArtistDetailMenu(m.baseMenu, m.artists[index].ID, m.artists[index].Name)
+}
+
+func (m *RelatedArtistMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+
+		res, err := m.spotifox.spotifyClient.GetRelatedArtists(m.spotifox.requestContext(m), m.artistId)
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get related artists failed"))
+		}
+
+		var artists []spotify.SimpleArtist
+		for _, artist := range res {
+			artists = append(artists, artist.SimpleArtist)
+		}
+		m.artists = artists
+		m.menus = utils.MenuItemsFromArtists(m.artists)
+
+		return true, nil
+	}
+}
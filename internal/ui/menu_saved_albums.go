@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+type SavedAlbumsMenu struct {
+	baseMenu
+	menus  []model.MenuItem
+	albums []spotify.SimpleAlbum
+
+	limit  int
+	offset int
+	total  int
+}
+
+func NewSavedAlbumsMenu(base baseMenu) *SavedAlbumsMenu {
+	return &SavedAlbumsMenu{
+		baseMenu: base,
+		limit:    50,
+	}
+}
+
+func (m *SavedAlbumsMenu) IsSearchable() bool {
+	return true
+}
+
+func (m *SavedAlbumsMenu) GetMenuKey() string {
+	return "saved_albums"
+}
+
+func (m *SavedAlbumsMenu) MenuViews() []model.MenuItem {
+	return m.menus
+}
+
+func (m *SavedAlbumsMenu) SubMenu(_ *model.App, index int) model.Menu {
+	if index >= len(m.albums) {
+		return nil
+	}
+	return NewAlbumDetailMenu(m.baseMenu, m.albums[index])
+}
+
+func (m *SavedAlbumsMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+
+		m.offset = 0
+		res, err := m.spotifox.spotifyClient.CurrentUsersAlbums(m.spotifox.requestContext(m), spotify.Limit(m.limit))
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get saved albums failed"))
+		}
+		m.total = res.Total
+
+		var albums []spotify.SimpleAlbum
+		for i := range res.Albums {
+			albums = append(albums, res.Albums[i].SimpleAlbum)
+		}
+		m.albums = albums
+		m.menus = utils.MenuItemsFromAlbums(m.albums)
+
+		return true, nil
+	}
+}
+
+func (m *SavedAlbumsMenu) BottomOutHook() model.Hook {
+	if m.total <= m.limit+m.offset {
+		return nil
+	}
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(BottomOutHookCallback(main, m))
+			return false, page
+		}
+
+		m.offset += m.limit
+		res, err := m.spotifox.spotifyClient.CurrentUsersAlbums(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get saved albums failed"))
+		}
+		for i := range res.Albums {
+			m.albums = append(m.albums, res.Albums[i].SimpleAlbum)
+		}
+		m.menus = utils.MenuItemsFromAlbums(m.albums)
+
+		return true, nil
+	}
+}
+
+func (m *SavedAlbumsMenu) Albums() []spotify.SimpleAlbum {
+	return m.albums
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+// searchAllTypes types of unified search, one section each
+const searchAllTypes = spotify.SearchTypeTrack | spotify.SearchTypeAlbum | spotify.SearchTypeArtist | spotify.SearchTypePlaylist | spotify.SearchTypeShow
+
+var searchSections = []struct {
+	searchType spotify.SearchType
+	title      string
+}{
+	{searchType: spotify.SearchTypeTrack, title: "search_section_tracks"},
+	{searchType: spotify.SearchTypeAlbum, title: "search_section_albums"},
+	{searchType: spotify.SearchTypeArtist, title: "search_section_artists"},
+	{searchType: spotify.SearchTypePlaylist, title: "search_section_playlists"},
+	{searchType: spotify.SearchTypeShow, title: "search_section_shows"},
+}
+
+// SearchMenu sections of unified search result
+type SearchMenu struct {
+	baseMenu
+	keyword string
+	result  *spotify.SearchResult
+	saved   *storage.SavedSearch
+}
+
+func NewSearchMenu(base baseMenu) *SearchMenu {
+	return &SearchMenu{
+		baseMenu: base,
+	}
+}
+
+// NewSavedSearchMenu search by the saved query when entering
+func NewSavedSearchMenu(base baseMenu, saved storage.SavedSearch) *SearchMenu {
+	return &SearchMenu{
+		baseMenu: base,
+		keyword:  saved.Query,
+		saved:    &saved,
+	}
+}
+
+func (m *SearchMenu) GetMenuKey() string {
+	if m.saved != nil {
+		return "saved_search_" + m.saved.Name
+	}
+	return "search_" + m.keyword
+}
+
+func (m *SearchMenu) FormatMenuItem(item *model.MenuItem) {
+	if m.saved != nil {
+		return
+	}
+	if m.keyword == "" {
+		item.Subtitle = ""
+		return
+	}
+	item.Subtitle = "「" + m.keyword + "」"
+}
+
+func (m *SearchMenu) MenuViews() []model.MenuItem {
+	var menus []model.MenuItem
+	for _, section := range searchSections {
+		count := locale.MustT("search_result_count", locale.WithTplData(map[string]int{"Count": m.sectionTotal(section.searchType)}))
+		menus = append(menus, model.MenuItem{Title: locale.MustT(section.title), Subtitle: "[" + count + "]"})
+	}
+	if m.saved != nil {
+		menus = append(menus, model.MenuItem{Title: locale.MustT("delete_saved_search"), Subtitle: m.saved.Name})
+	}
+	return menus
+}
+
+func (m *SearchMenu) BeforeEnterMenuHook() model.Hook {
+	if m.saved != nil {
+		return m.searchSavedQuery
+	}
+	return func(main *model.Main) (bool, model.Page) {
+		if keyword, result := m.spotifox.search.takeResult(); result != nil {
+			m.keyword, m.result = keyword, result
+			return true, nil
+		}
+		if m.result == nil {
+			page, _ := m.spotifox.ToSearchPage()
+			return false, page
+		}
+		return true, nil
+	}
+}
+
+func (m *SearchMenu) BeforeBackMenuHook() model.Hook {
+	return m.backMenuHook(func(main *model.Main) (bool, model.Page) {
+		// search again when entering next time
+		if m.saved == nil {
+			m.keyword = ""
+		}
+		m.result = nil
+		return true, nil
+	})
+}
+
+func (m *SearchMenu) searchSavedQuery(main *model.Main) (bool, model.Page) {
+	if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+		page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+		return false, page
+	}
+
+	res, err := m.spotifox.spotifyClient.Search(m.spotifox.requestContext(m), m.keyword, searchAllTypes, m.spotifox.searchOptions(0)...)
+	if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+		return false, page
+	}
+	if err != nil {
+		return m.handleFetchErr(errors.Wrap(err, "search saved query failed"))
+	}
+	m.result = res
+	return true, nil
+}
+
+func (m *SearchMenu) SubMenu(app *model.App, index int) model.Menu {
+	if index == len(searchSections) && m.saved != nil {
+		m.spotifox.RemoveSavedSearch(m.saved.Name)
+		main := app.MustMain()
+		main.BackMenu()
+		main.RefreshMenuList()
+		return nil
+	}
+	if index >= len(searchSections) || m.result == nil {
+		return nil
+	}
+	return NewSearchResultMenu(m.baseMenu, m.keyword, searchSections[index].searchType, m.result)
+}
+
+func (m *SearchMenu) sectionTotal(searchType spotify.SearchType) int {
+	if m.result == nil {
+		return 0
+	}
+	switch searchType {
+	case spotify.SearchTypeTrack:
+		if m.result.Tracks != nil {
+			return m.result.Tracks.Total
+		}
+	case spotify.SearchTypeAlbum:
+		if m.result.Albums != nil {
+			return m.result.Albums.Total
+		}
+	case spotify.SearchTypeArtist:
+		if m.result.Artists != nil {
+			return m.result.Artists.Total
+		}
+	case spotify.SearchTypePlaylist:
+		if m.result.Playlists != nil {
+			return m.result.Playlists.Total
+		}
+	case spotify.SearchTypeShow:
+		if m.result.Shows != nil {
+			return m.result.Shows.Total
+		}
+	}
+	return 0
+}
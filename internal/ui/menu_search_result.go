@@ -1,20 +1,22 @@
 package ui
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
+	"github.com/skratchdot/open-golang/open"
 	"github.com/zmb3/spotify/v2"
 )
 
+// SearchResultMenu one section of search result, paginated independently
 type SearchResultMenu struct {
 	baseMenu
 	menus      []model.MenuItem
 	offset     int
+	total      int
 	searchType spotify.SearchType
 	keyword    string
 	result     any
@@ -25,32 +27,25 @@ var playableTypes = map[spotify.SearchType]bool{
 	spotify.SearchTypeAlbum:    false,
 	spotify.SearchTypeArtist:   false,
 	spotify.SearchTypePlaylist: false,
-	// spotify.SearchTypeShow:     false,
+	spotify.SearchTypeShow:     false,
 	// spotify.SearchTypeEpisode:  false,
 }
 
-func NewSearchResultMenu(base baseMenu, searchType spotify.SearchType) *SearchResultMenu {
-	return &SearchResultMenu{
+func NewSearchResultMenu(base baseMenu, keyword string, searchType spotify.SearchType, res *spotify.SearchResult) *SearchResultMenu {
+	m := &SearchResultMenu{
 		baseMenu:   base,
 		offset:     0,
 		searchType: searchType,
+		keyword:    keyword,
 	}
+	m.appendResult(res)
+	return m
 }
 
 func (m *SearchResultMenu) IsSearchable() bool {
 	return true
 }
 
-func (m *SearchResultMenu) BeforeBackMenuHook() model.Hook {
-	return func(main *model.Main) (bool, model.Page) {
-		if m.spotifox.search.wordsInput.Value() != "" {
-			m.spotifox.search.wordsInput.SetValue("")
-		}
-
-		return true, nil
-	}
-}
-
 func (m *SearchResultMenu) IsPlayable() bool {
 	return playableTypes[m.searchType]
 }
@@ -60,7 +55,7 @@ func (m *SearchResultMenu) GetMenuKey() string {
 }
 
 func (m *SearchResultMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.Songs())
 }
 
 func (m *SearchResultMenu) SubMenu(_ *model.App, index int) model.Menu {
@@ -82,16 +77,29 @@ func (m *SearchResultMenu) SubMenu(_ *model.App, index int) model.Menu {
 			return nil
 		}
 		return NewArtistDetailMenu(m.baseMenu, resultWithType[index].ID, resultWithType[index].Name)
+	case []spotify.FullShow:
+		// shows can't be played by spotifox now
+		if index < len(resultWithType) {
+			_ = open.Start(utils.WebURLOfShow(resultWithType[index].ID))
+		}
+		return nil
 	}
 
 	return nil
 }
 
 func (m *SearchResultMenu) BottomOutHook() model.Hook {
+	if m.total <= m.offset+types.SearchPageSize {
+		return nil
+	}
 	return func(main *model.Main) (bool, model.Page) {
-		m.offset += types.SearchPageSize
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(BottomOutHookCallback(main, m))
+			return false, page
+		}
 
-		res, err := m.spotifox.spotifyClient.Search(context.Background(), m.keyword, m.searchType, spotify.Limit(types.SearchPageSize), spotify.Offset(m.offset))
+		m.offset += types.SearchPageSize
+		res, err := m.spotifox.spotifyClient.Search(m.spotifox.requestContext(m), m.keyword, m.searchType, m.spotifox.searchOptions(m.offset)...)
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -99,48 +107,57 @@ func (m *SearchResultMenu) BottomOutHook() model.Hook {
 			return m.handleFetchErr(errors.Wrap(err, "search item failed"))
 		}
 
-		switch m.searchType {
-		case spotify.SearchTypeTrack:
-			var tracks, _ = m.result.([]spotify.FullTrack)
-			m.result = append(tracks, res.Tracks.Tracks...)
-		case spotify.SearchTypeAlbum:
-			var albums, _ = m.result.([]spotify.SimpleAlbum)
-			m.result = append(albums, res.Albums.Albums...)
-		case spotify.SearchTypeArtist:
-			var artists, _ = m.result.([]spotify.SimpleArtist)
-			for _, artist := range res.Artists.Artists {
-				artists = append(artists, artist.SimpleArtist)
-			}
-			m.result = artists
-		case spotify.SearchTypePlaylist:
-			var playlists, _ = m.result.([]spotify.SimplePlaylist)
-			m.result = append(playlists, res.Playlists.Playlists...)
-		case spotify.SearchTypeShow:
-			var shows, _ = m.result.([]spotify.FullShow)
-			m.result = append(shows, res.Shows.Shows...)
-		case spotify.SearchTypeEpisode:
-			var episodes, _ = m.result.([]spotify.EpisodePage)
-			m.result = append(episodes, res.Episodes.Episodes...)
-		}
-
-		m.convertMenus()
+		m.appendResult(res)
 		return true, nil
 	}
 }
 
-func (m *SearchResultMenu) BeforeEnterMenuHook() model.Hook {
-	return func(main *model.Main) (bool, model.Page) {
-		if m.spotifox.search.wordsInput.Value() == "" {
-			page, _ := m.spotifox.ToSearchPage(m.searchType)
-			return false, page
+// appendResult appends the page of current search type to result
+func (m *SearchResultMenu) appendResult(res *spotify.SearchResult) {
+	if res == nil {
+		return
+	}
+	switch m.searchType {
+	case spotify.SearchTypeTrack:
+		var tracks, _ = m.result.([]spotify.FullTrack)
+		if res.Tracks != nil {
+			tracks = append(tracks, res.Tracks.Tracks...)
+			m.total = res.Tracks.Total
 		}
-
-		m.result = m.spotifox.search.result
-		m.searchType = m.spotifox.search.searchType
-		m.keyword = m.spotifox.search.wordsInput.Value()
-		m.convertMenus()
-		return true, nil
+		m.result = tracks
+	case spotify.SearchTypeAlbum:
+		var albums, _ = m.result.([]spotify.SimpleAlbum)
+		if res.Albums != nil {
+			albums = append(albums, res.Albums.Albums...)
+			m.total = res.Albums.Total
+		}
+		m.result = albums
+	case spotify.SearchTypeArtist:
+		var artists, _ = m.result.([]spotify.SimpleArtist)
+		if res.Artists != nil {
+			for _, artist := range res.Artists.Artists {
+				artists = append(artists, artist.SimpleArtist)
+			}
+			m.total = res.Artists.Total
+		}
+		m.result = artists
+	case spotify.SearchTypePlaylist:
+		var playlists, _ = m.result.([]spotify.SimplePlaylist)
+		if res.Playlists != nil {
+			playlists = append(playlists, res.Playlists.Playlists...)
+			m.total = res.Playlists.Total
+		}
+		m.result = playlists
+	case spotify.SearchTypeShow:
+		var shows, _ = m.result.([]spotify.FullShow)
+		if res.Shows != nil {
+			shows = append(shows, res.Shows.Shows...)
+			m.total = res.Shows.Total
+		}
+		m.result = shows
 	}
+
+	m.convertMenus()
 }
 
 func (m *SearchResultMenu) convertMenus() {
@@ -153,6 +170,8 @@ func (m *SearchResultMenu) convertMenus() {
 		m.menus = utils.MenuItemsFromPlaylists(resultWithType)
 	case []spotify.SimpleArtist:
 		m.menus = utils.MenuItemsFromArtists(resultWithType)
+	case []spotify.FullShow:
+		m.menus = utils.MenuItemsFromShows(resultWithType)
 	}
 }
 
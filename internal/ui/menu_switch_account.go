@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/structs"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+)
+
+// SwitchAccountMenu lists profiles, each of them has its own account, Last.fm session and play state
+type SwitchAccountMenu struct {
+	baseMenu
+	profiles storage.Profiles
+	// accounts display names of the accounts logged in by profiles
+	accounts map[string]string
+}
+
+func NewSwitchAccountMenu(base baseMenu) *SwitchAccountMenu {
+	return &SwitchAccountMenu{
+		baseMenu: base,
+	}
+}
+
+func (m *SwitchAccountMenu) GetMenuKey() string {
+	return "switch_account"
+}
+
+func (m *SwitchAccountMenu) FormatMenuItem(item *model.MenuItem) {
+	item.Subtitle = "[" + storage.CurProfile() + "]"
+}
+
+func (m *SwitchAccountMenu) MenuViews() []model.MenuItem {
+	var menus []model.MenuItem
+	for _, name := range m.profiles.Names {
+		var subtitle string
+		if account, ok := m.accounts[name]; ok {
+			subtitle = "[" + utils.ReplaceSpecialStr(account) + "]"
+		} else {
+			subtitle = "[" + locale.MustT("no_login") + "]"
+		}
+		if name == storage.CurProfile() {
+			subtitle += " [" + locale.MustT("current_profile") + "]"
+		}
+		menus = append(menus, model.MenuItem{Title: name, Subtitle: subtitle})
+	}
+	// where the settings of profile are read from
+	return append(menus, model.MenuItem{Title: locale.MustT("add_account"), Subtitle: "[" + locale.MustT("settings_overridden_by_profile") + "]"})
+}
+
+func (m *SwitchAccountMenu) BeforeEnterMenuHook() model.Hook {
+	return func(_ *model.Main) (bool, model.Page) {
+		m.refresh()
+		return true, nil
+	}
+}
+
+func (m *SwitchAccountMenu) refresh() {
+	m.profiles = storage.LoadProfiles()
+	m.accounts = make(map[string]string)
+
+	table := storage.NewTable()
+	for _, name := range m.profiles.Names {
+		jsonStr, err := table.GetByKVModel(storage.User{Profile: name})
+		if err != nil {
+			continue
+		}
+		user, err := structs.NewUserFromLocalJson(jsonStr)
+		if err != nil {
+			continue
+		}
+		if user.DisplayName != "" {
+			m.accounts[name] = user.DisplayName
+		} else {
+			m.accounts[name] = user.Username
+		}
+	}
+}
+
+func (m *SwitchAccountMenu) SubMenu(app *model.App, index int) model.Menu {
+	name := m.profiles.NextName()
+	if index < len(m.profiles.Names) {
+		name = m.profiles.Names[index]
+	}
+	if name == storage.CurProfile() {
+		return nil
+	}
+
+	main := app.MustMain()
+	loading := model.NewLoading(main)
+	loading.Start()
+	m.spotifox.SwitchProfile(name, func(err error) {
+		loading.Complete()
+		if err != nil {
+			utils.Logger().Printf("switch profile failed: %+v", err)
+			model.NewMenuTips(main, nil).DisplayTips("Err:" + err.Error())
+			return
+		}
+
+		m.refresh()
+		main.RefreshMenuList()
+		main.RefreshMenuTitle()
+
+		tips := locale.MustT("switched_to_profile", locale.WithTplData(map[string]string{"Profile": name}))
+		if m.spotifox.user == nil {
+			tips += ", " + locale.MustT("login_after_switch")
+		}
+		model.NewMenuTips(main, nil).DisplayTips(tips)
+	})
+	return nil
+}
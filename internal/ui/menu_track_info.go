@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+var pitchClassNames = [12]string{"C", "C♯/D♭", "D", "D♯/E♭", "E", "F", "F♯/G♭", "G", "G♯/A♭", "A", "A♯/B♭", "B"}
+
+// camelot wheel notation, index by pitch class
+var (
+	camelotMajor = [12]string{"8B", "3B", "10B", "5B", "12B", "7B", "2B", "9B", "4B", "11B", "6B", "1B"}
+	camelotMinor = [12]string{"5A", "12A", "7A", "2A", "9A", "4A", "11A", "6A", "1A", "8A", "3A", "10A"}
+)
+
+// rows of track info, the audio features follow them
+const (
+	trackInfoName = iota
+	trackInfoArtists
+	trackInfoAlbum
+	trackInfoReleaseDate
+	trackInfoNumber
+	trackInfoDuration
+	trackInfoPopularity
+	trackInfoISRC
+	trackInfoExplicit
+	trackInfoMarkets
+)
+
+type TrackInfoMenu struct {
+	baseMenu
+	menus    []model.MenuItem
+	song     spotify.FullTrack
+	features *spotify.AudioFeatures
+}
+
+func NewTrackInfoMenu(base baseMenu, song spotify.FullTrack) *TrackInfoMenu {
+	return &TrackInfoMenu{
+		baseMenu: base,
+		song:     song,
+	}
+}
+
+func (m *TrackInfoMenu) GetMenuKey() string {
+	return "track_info_" + string(m.song.ID)
+}
+
+func (m *TrackInfoMenu) MenuViews() []model.MenuItem {
+	return m.menus
+}
+
+func (m *TrackInfoMenu) SubMenu(_ *model.App, index int) model.Menu {
+	if index == trackInfoAlbum && m.song.Album.ID != "" {
+		return NewAlbumDetailMenu(m.baseMenu, m.song.Album)
+	}
+	return nil
+}
+
+func (m *TrackInfoMenu) BeforeEnterMenuHook() model.Hook {
+	return func(main *model.Main) (bool, model.Page) {
+		if m.spotifox.CheckAuthSession() == utils.NeedLogin {
+			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
+			return false, page
+		}
+
+		// songs of album detail don't contain popularity and external ids
+		track, err := m.spotifox.spotifyClient.GetTrack(m.spotifox.requestContext(m), m.song.ID)
+		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
+			return false, page
+		}
+		if err != nil {
+			return m.handleFetchErr(errors.Wrap(err, "get track failed"))
+		}
+		m.song = *track
+
+		if m.features, err = m.spotifox.FetchAudioFeatures(m.song.ID); err != nil {
+			utils.Logger().Printf("get audio features failed: %+v", err)
+		}
+
+		m.menus = m.trackMenuItems()
+		return true, nil
+	}
+}
+
+func (m *TrackInfoMenu) trackMenuItems() []model.MenuItem {
+	song := &m.song
+	explicit := locale.MustT("no")
+	if song.Explicit {
+		explicit = locale.MustT("yes")
+	}
+	isrc := song.ExternalIDs["isrc"]
+	if isrc == "" {
+		isrc = "-"
+	}
+	releaseDate := song.Album.ReleaseDate
+	if releaseDate == "" {
+		releaseDate = "-"
+	}
+	markets := fmt.Sprintf("%d", len(song.AvailableMarkets))
+	if len(song.AvailableMarkets) > 0 {
+		markets += " [" + strings.Join(song.AvailableMarkets, ",") + "]"
+	}
+
+	menus := []model.MenuItem{
+		trackInfoName:        {Title: locale.MustT("track_name"), Subtitle: utils.ReplaceSpecialStr(song.Name)},
+		trackInfoArtists:     {Title: locale.MustT("track_artists"), Subtitle: utils.ReplaceSpecialStr(utils.ArtistNameStrOfSong(song))},
+		trackInfoAlbum:       {Title: locale.MustT("track_album"), Subtitle: utils.ReplaceSpecialStr(song.Album.Name)},
+		trackInfoReleaseDate: {Title: locale.MustT("release_date"), Subtitle: releaseDate},
+		trackInfoNumber:      {Title: locale.MustT("track_number"), Subtitle: fmt.Sprintf("%d-%d", song.DiscNumber, song.TrackNumber)},
+		trackInfoDuration:    {Title: locale.MustT("track_duration"), Subtitle: formatDuration(song.TimeDuration())},
+		trackInfoPopularity:  {Title: locale.MustT("popularity"), Subtitle: strconv.Itoa(song.Popularity)},
+		trackInfoISRC:        {Title: "ISRC", Subtitle: isrc},
+		trackInfoExplicit:    {Title: locale.MustT("explicit"), Subtitle: explicit},
+		trackInfoMarkets:     {Title: locale.MustT("available_markets"), Subtitle: markets},
+	}
+
+	if m.features == nil {
+		return append(menus, model.MenuItem{Title: locale.MustT("audio_features"), Subtitle: locale.MustT("audio_features_unavailable")})
+	}
+
+	f := m.features
+	return append(menus,
+		model.MenuItem{Title: locale.MustT("tempo"), Subtitle: fmt.Sprintf("%.1f BPM", f.Tempo)},
+		model.MenuItem{Title: locale.MustT("musical_key"), Subtitle: musicalKeyName(f.Key, f.Mode)},
+		model.MenuItem{Title: locale.MustT("time_signature"), Subtitle: fmt.Sprintf("%d/4", f.TimeSignature)},
+		model.MenuItem{Title: locale.MustT("energy"), Subtitle: fmt.Sprintf("%.2f", f.Energy)},
+		model.MenuItem{Title: locale.MustT("danceability"), Subtitle: fmt.Sprintf("%.2f", f.Danceability)},
+		model.MenuItem{Title: locale.MustT("valence"), Subtitle: fmt.Sprintf("%.2f", f.Valence)},
+		model.MenuItem{Title: locale.MustT("loudness"), Subtitle: fmt.Sprintf("%.1f dB", f.Loudness)},
+	)
+}
+
+// musicalKeyName format pitch class and mode, e.g. "A minor (8A)"
+func musicalKeyName(key, mode int) string {
+	if key < 0 || key >= len(pitchClassNames) {
+		return "-"
+	}
+	if spotify.Mode(mode) == spotify.Major {
+		return fmt.Sprintf("%s %s (%s)", pitchClassNames[key], locale.MustT("major"), camelotMajor[key])
+	}
+	return fmt.Sprintf("%s %s (%s)", pitchClassNames[key], locale.MustT("minor"), camelotMinor[key])
+}
+
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
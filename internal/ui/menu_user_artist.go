@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"context"
-
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
@@ -55,7 +53,7 @@ func (m *UserArtistMenu) BeforeEnterMenuHook() model.Hook {
 			return false, page
 		}
 
-		res, err := m.spotifox.spotifyClient.CurrentUsersFollowedArtists(context.Background(), spotify.Limit(m.limit))
+		res, err := m.spotifox.spotifyClient.CurrentUsersFollowedArtists(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -86,7 +84,7 @@ func (m *UserArtistMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		res, err := m.spotifox.spotifyClient.CurrentUsersFollowedArtists(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err := m.spotifox.spotifyClient.CurrentUsersFollowedArtists(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"context"
-
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/pkg/errors"
@@ -64,9 +62,9 @@ func (m *UserPlaylistMenu) BeforeEnterMenuHook() model.Hook {
 			err error
 		)
 		if m.userId == CurUser {
-			res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(context.Background(), spotify.Limit(m.limit))
+			res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		} else {
-			res, err = m.spotifox.spotifyClient.GetPlaylistsForUser(context.Background(), m.userId, spotify.Limit(m.limit))
+			res, err = m.spotifox.spotifyClient.GetPlaylistsForUser(m.spotifox.requestContext(m), m.userId, spotify.Limit(m.limit))
 		}
 
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
@@ -100,9 +98,9 @@ func (m *UserPlaylistMenu) BottomOutHook() model.Hook {
 			err error
 		)
 		if m.userId == CurUser {
-			res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+			res, err = m.spotifox.spotifyClient.CurrentUsersPlaylists(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		} else {
-			res, err = m.spotifox.spotifyClient.GetPlaylistsForUser(context.Background(), m.userId, spotify.Limit(m.limit), spotify.Offset(m.offset))
+			res, err = m.spotifox.spotifyClient.GetPlaylistsForUser(m.spotifox.requestContext(m), m.userId, spotify.Limit(m.limit), spotify.Offset(m.offset))
 		}
 
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
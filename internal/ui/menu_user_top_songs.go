@@ -17,12 +17,23 @@ type UserTopSongsMenu struct {
 	limit  int
 	offset int
 	total  int
+	loader *songsLoader
 }
 
 func NewUserTopSongsMenu(base baseMenu) *UserTopSongsMenu {
-	return &UserTopSongsMenu{
+	m := &UserTopSongsMenu{
 		baseMenu: base,
+
+		limit: 50,
 	}
+	m.loader = newSongsLoader(m.limit, base.spotifox.runOnUI, func(ctx context.Context, offset, limit int) ([]spotify.FullTrack, error) {
+		res, err := m.spotifox.spotifyClient.CurrentUsersTopTracks(ctx, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return nil, err
+		}
+		return res.Tracks, nil
+	})
+	return m
 }
 
 func (m *UserTopSongsMenu) IsSearchable() bool {
@@ -38,7 +49,7 @@ func (m *UserTopSongsMenu) GetMenuKey() string {
 }
 
 func (m *UserTopSongsMenu) MenuViews() []model.MenuItem {
-	return m.menus
+	return m.spotifox.likedSongs.markLiked(m.menus, m.songs)
 }
 
 func (m *UserTopSongsMenu) BeforeEnterMenuHook() model.Hook {
@@ -47,7 +58,10 @@ func (m *UserTopSongsMenu) BeforeEnterMenuHook() model.Hook {
 			page, _ := m.spotifox.ToLoginPage(EnterMenuCallback(main))
 			return false, page
 		}
-		res, err := m.spotifox.spotifyClient.CurrentUsersTopTracks(context.Background(), spotify.Limit(m.limit))
+		// the songs loaded partially are dropped
+		m.loader.Cancel()
+		m.songs, m.menus, m.offset, m.total = nil, nil, 0, 0
+		res, err := m.spotifox.spotifyClient.CurrentUsersTopTracks(m.spotifox.requestContext(m), spotify.Limit(m.limit))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), EnterMenuCallback(main)); catched {
 			return false, page
 		}
@@ -74,7 +88,7 @@ func (m *UserTopSongsMenu) BottomOutHook() model.Hook {
 		}
 
 		m.offset += m.limit
-		res, err := m.spotifox.spotifyClient.CurrentUsersTopTracks(context.Background(), spotify.Limit(m.limit), spotify.Offset(m.offset))
+		res, err := m.spotifox.spotifyClient.CurrentUsersTopTracks(m.spotifox.requestContext(m), spotify.Limit(m.limit), spotify.Offset(m.offset))
 		if catched, page := m.spotifox.HandleResCode(utils.CheckSpotifyErr(err), BottomOutHookCallback(main, m)); catched {
 			return false, page
 		}
@@ -91,3 +105,20 @@ func (m *UserTopSongsMenu) BottomOutHook() model.Hook {
 func (m *UserTopSongsMenu) Songs() []spotify.FullTrack {
 	return m.songs
 }
+
+func (m *UserTopSongsMenu) LoadAllSongs(onProgress func(loaded, total int), onComplete func(songs []spotify.FullTrack)) bool {
+	if m.total <= m.limit+m.offset {
+		return false
+	}
+	return m.loader.Load(m.songs, m.limit+m.offset, m.total, onProgress, func(songs []spotify.FullTrack) {
+		if songs == nil {
+			onComplete(nil)
+			return
+		}
+		m.songs = songs
+		m.menus = utils.MenuItemsFromSongs(m.songs)
+		// all pages are loaded, disable BottomOutHook
+		m.offset = m.total
+		onComplete(songs)
+	})
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anhoder/foxful-cli/model"
+	"github.com/anhoder/foxful-cli/util"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+const PageTypeOpenLink model.PageType = "open_link"
+
+type tickOpenLinkMsg struct{}
+
+func tickOpenLink(duration time.Duration) tea.Cmd {
+	return tea.Tick(duration, func(t time.Time) tea.Msg {
+		return tickOpenLinkMsg{}
+	})
+}
+
+// OpenLinkPage input of spotify link or uri
+type OpenLinkPage struct {
+	spotifox  *Spotifox
+	menuTitle *model.MenuItem
+
+	linkInput textinput.Model
+	tips      string
+}
+
+func NewOpenLinkPage(spotifox *Spotifox) (page *OpenLinkPage) {
+	page = &OpenLinkPage{
+		spotifox:  spotifox,
+		menuTitle: &model.MenuItem{Title: locale.MustT("open_link"), Subtitle: locale.MustT("open_link_supported")},
+		linkInput: textinput.New(),
+	}
+	page.linkInput.Placeholder = " " + locale.MustT("input_link")
+	page.linkInput.Focus()
+	page.linkInput.Prompt = model.GetFocusedPrompt()
+	page.linkInput.TextStyle = util.GetPrimaryFontStyle()
+	page.linkInput.CharLimit = searchCharLimit
+	return
+}
+
+func (p *OpenLinkPage) IgnoreQuitKeyMsg(_ tea.KeyMsg) bool {
+	return true
+}
+
+func (p *OpenLinkPage) Type() model.PageType {
+	return PageTypeOpenLink
+}
+
+func (p *OpenLinkPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
+	if _, ok := msg.(tickOpenLinkMsg); ok {
+		return p, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if ok {
+		switch key.String() {
+		case "esc":
+			p.Reset()
+			return p.spotifox.MustMain(), p.spotifox.RerenderCmd(true)
+		case "enter":
+			return p.enterHandler()
+		}
+	}
+
+	var cmd tea.Cmd
+	p.linkInput, cmd = p.linkInput.Update(msg)
+	return p, cmd
+}
+
+func (p *OpenLinkPage) enterHandler() (model.Page, tea.Cmd) {
+	link := strings.TrimSpace(p.linkInput.Value())
+	if _, _, err := utils.ParseSpotifyLink(link); err != nil {
+		p.tips = util.SetFgStyle(locale.MustT("invalid_link"), termenv.ANSIBrightRed)
+		return p, nil
+	}
+	p.Reset()
+
+	main := p.spotifox.MustMain()
+	if page := openSpotifyLink(p.spotifox, link); page != nil {
+		return page, func() tea.Msg { return page.Msg() }
+	}
+	return main, p.spotifox.RerenderCmd(true)
+}
+
+func (p *OpenLinkPage) View(a *model.App) string {
+	var (
+		builder strings.Builder
+		top     int
+		main    = p.spotifox.MustMain()
+	)
+
+	// title
+	if configs.ConfigRegistry.Main.ShowTitle {
+		builder.WriteString(main.TitleView(a, &top))
+	} else {
+		top++
+	}
+
+	// menu title
+	builder.WriteString(main.MenuTitleView(a, &top, p.menuTitle))
+	builder.WriteString("\n\n\n")
+	top += 2
+
+	if main.MenuStartColumn() > 0 {
+		builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()))
+	}
+	builder.WriteString(p.linkInput.View())
+	valueLen := runewidth.StringWidth(p.linkInput.Value())
+	if valueLen == 0 {
+		valueLen = runewidth.StringWidth(p.linkInput.Placeholder)
+	}
+	if spaceLen := a.WindowWidth() - main.MenuStartColumn() - valueLen - 3; spaceLen > 0 {
+		builder.WriteString(strings.Repeat(" ", spaceLen))
+	}
+	top++
+
+	builder.WriteString("\n\n")
+	top++
+	if main.MenuStartColumn() > 0 {
+		builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()))
+	}
+	builder.WriteString(p.tips)
+	builder.WriteString("\n")
+
+	if a.WindowHeight() > top+3 {
+		builder.WriteString(strings.Repeat("\n", a.WindowHeight()-top-3))
+	}
+
+	return builder.String()
+}
+
+func (p *OpenLinkPage) Msg() tea.Msg {
+	return &tickOpenLinkMsg{}
+}
+
+func (p *OpenLinkPage) Reset() {
+	p.tips = ""
+	p.linkInput.SetValue("")
+	p.linkInput.Reset()
+	p.linkInput.Focus()
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"os"
 	"path"
+	"time"
 
 	"github.com/anhoder/foxful-cli/model"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/zmb3/spotify/v2"
 
+	"github.com/go-musicfox/spotifox/internal/configs"
 	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
@@ -36,6 +38,7 @@ func likePlayingSong(m *Spotifox, likeOrNot bool) model.Page {
 		return nil
 	}
 	m.player.isCurSongLiked = likeOrNot
+	syncLastfmLove(m, m.player.playlist[m.player.curSongIndex], likeOrNot)
 
 	var title = locale.MustT("like_song_success")
 	if !likeOrNot {
@@ -50,9 +53,100 @@ func likePlayingSong(m *Spotifox, likeOrNot bool) model.Page {
 	return nil
 }
 
+// syncLastfmLove loves or unloves the song on Last.fm if lastfmLoveSync is enabled,
+// the artists are the same as scrobbled
+func syncLastfmLove(m *Spotifox, song spotify.FullTrack, loveOrNot bool) {
+	if !configs.ConfigRegistry.Main.LastfmLoveSync || !m.lastfm.Authed() {
+		return
+	}
+	go func() {
+		defer utils.Recover(true)
+		if err := m.lastfm.Love(utils.ArtistNameStrOfSong(&song), song.Name, loveOrNot); err != nil {
+			utils.Logger().Printf("sync love of %s to lastfm failed: %+v", song.ID, err)
+		}
+	}()
+}
+
+func saveAlbumOfPlayingSong(m *Spotifox, saveOrNot bool) model.Page {
+	loading := model.NewLoading(m.MustMain())
+	loading.Start()
+	defer loading.Complete()
+
+	if m.player.curSongIndex >= len(m.player.playlist) {
+		return nil
+	}
+
+	if m.CheckAuthSession() == utils.NeedLogin {
+		page, _ := m.ToLoginPage(func() model.Page {
+			saveAlbumOfPlayingSong(m, saveOrNot)
+			return nil
+		})
+		return page
+	}
+
+	album := m.player.playlist[m.player.curSongIndex].Album
+	if album.ID == "" || !m.SaveAlbum(album.ID, saveOrNot) {
+		return nil
+	}
+
+	var title = locale.MustT("save_album_success")
+	if !saveOrNot {
+		title = locale.MustT("unsave_album_success")
+	}
+	utils.Notify(utils.NotifyContent{
+		Title:   title,
+		Text:    album.Name,
+		Url:     utils.WebURLOfAlbum(album.ID),
+		GroupId: types.GroupID,
+	})
+	return nil
+}
+
+func saveSelectedAlbum(m *Spotifox, saveOrNot bool) model.Page {
+	loading := model.NewLoading(m.MustMain())
+	loading.Start()
+	defer loading.Complete()
+
+	var (
+		main = m.MustMain()
+		menu = main.CurMenu()
+	)
+	me, ok := menu.(AlbumsMenu)
+	selectedIndex := menu.RealDataIndex(main.SelectedIndex())
+	if !ok || selectedIndex >= len(me.Albums()) {
+		return nil
+	}
+	albums := me.Albums()
+
+	if m.CheckAuthSession() == utils.NeedLogin {
+		page, _ := m.ToLoginPage(func() model.Page {
+			saveSelectedAlbum(m, saveOrNot)
+			return nil
+		})
+		return page
+	}
+
+	if !m.SaveAlbum(albums[selectedIndex].ID, saveOrNot) {
+		return nil
+	}
+
+	var title = locale.MustT("save_album_success")
+	if !saveOrNot {
+		title = locale.MustT("unsave_album_success")
+	}
+	utils.Notify(utils.NotifyContent{
+		Title:   title,
+		Text:    albums[selectedIndex].Name,
+		Url:     utils.WebURLOfAlbum(albums[selectedIndex].ID),
+		GroupId: types.GroupID,
+	})
+	return nil
+}
+
 func logout(clearAll bool) {
 	table := storage.NewTable()
 	_ = table.DeleteByKVModel(storage.User{})
+	_ = table.DeleteByKVModel(storage.LikedSongs{})
 	if clearAll {
 		(&storage.LastfmUser{}).Clear()
 	}
@@ -92,6 +186,7 @@ func likeSelectedSong(m *Spotifox, likeOrNot bool) model.Page {
 	if !m.LikeSong(songs[selectedIndex].ID, likeOrNot) {
 		return nil
 	}
+	syncLastfmLove(m, songs[selectedIndex], likeOrNot)
 
 	var title = locale.MustT("like_song_success")
 	if !likeOrNot {
@@ -215,6 +310,40 @@ func artistOfSelectedSong(m *Spotifox) {
 	main.EnterMenu(NewArtistsOfSongMenu(newBaseMenu(m), song), &model.MenuItem{Title: locale.MustT("artist_of_track", locale.WithTplData(map[string]string{"TrackName": song.Name}))})
 }
 
+func trackInfoOfPlayingSong(m *Spotifox) model.Page {
+	var (
+		main = m.MustMain()
+		menu = main.CurMenu()
+	)
+	if m.player.curSongIndex >= len(m.player.playlist) {
+		return nil
+	}
+	curSong := m.player.playlist[m.player.curSongIndex]
+	if info, ok := menu.(*TrackInfoMenu); ok && info.song.ID == curSong.ID {
+		return nil
+	}
+
+	return main.EnterMenu(NewTrackInfoMenu(newBaseMenu(m), curSong), &model.MenuItem{Title: locale.MustT("track_info"), Subtitle: curSong.Name})
+}
+
+func trackInfoOfSelectedSong(m *Spotifox) model.Page {
+	var (
+		main = m.MustMain()
+		menu = main.CurMenu()
+	)
+	me, ok := menu.(SongsMenu)
+	selectedIndex := menu.RealDataIndex(main.SelectedIndex())
+	if !ok || selectedIndex >= len(me.Songs()) {
+		return nil
+	}
+	song := me.Songs()[selectedIndex]
+	if info, ok := menu.(*TrackInfoMenu); ok && info.song.ID == song.ID {
+		return nil
+	}
+
+	return main.EnterMenu(NewTrackInfoMenu(newBaseMenu(m), song), &model.MenuItem{Title: locale.MustT("track_info"), Subtitle: song.Name})
+}
+
 func openPlayingSongInWeb(m *Spotifox) {
 	loading := model.NewLoading(m.MustMain())
 	loading.Start()
@@ -407,3 +536,84 @@ func addSongToUserPlaylist(m *Spotifox, isAdd bool) model.Page {
 	}
 	return nil
 }
+
+// openSpotifyLink opens the detail menu of the link, or plays it when it's a track
+func openSpotifyLink(m *Spotifox, link string) model.Page {
+	main := m.MustMain()
+	linkType, id, err := utils.ParseSpotifyLink(link)
+	if err != nil {
+		model.NewMenuTips(main, nil).DisplayTips(locale.MustT("invalid_link"))
+		return nil
+	}
+
+	loading := model.NewLoading(main)
+	loading.Start()
+	defer loading.Complete()
+
+	if m.CheckAuthSession() == utils.NeedLogin {
+		page, _ := m.ToLoginPage(func() model.Page {
+			return openSpotifyLink(m, link)
+		})
+		return page
+	}
+
+	ctx := context.Background()
+	switch linkType {
+	case utils.SpotifyLinkTrack:
+		var track *spotify.FullTrack
+		if track, err = m.spotifyClient.GetTrack(ctx, id); err == nil {
+			return playSongNext(m, *track)
+		}
+	case utils.SpotifyLinkAlbum:
+		var album *spotify.FullAlbum
+		if album, err = m.spotifyClient.GetAlbum(ctx, id); err == nil {
+			return main.EnterMenu(NewAlbumDetailMenu(newBaseMenu(m), album.SimpleAlbum), &model.MenuItem{Title: album.Name, Subtitle: utils.ArtistNameStrOfAlbum(&album.SimpleAlbum)})
+		}
+	case utils.SpotifyLinkPlaylist:
+		var playlist *spotify.FullPlaylist
+		if playlist, err = m.spotifyClient.GetPlaylist(ctx, id, spotify.Fields("name,owner")); err == nil {
+			return main.EnterMenu(NewPlaylistDetailMenu(newBaseMenu(m), id), &model.MenuItem{Title: playlist.Name, Subtitle: playlist.Owner.DisplayName})
+		}
+	case utils.SpotifyLinkArtist:
+		var artist *spotify.FullArtist
+		if artist, err = m.spotifyClient.GetArtist(ctx, id); err == nil {
+			return main.EnterMenu(NewArtistDetailMenu(newBaseMenu(m), id, artist.Name), &model.MenuItem{Title: artist.Name})
+		}
+	case utils.SpotifyLinkShow:
+		// shows and episodes can't be played by spotifox now
+		_ = open.Start(utils.WebURLOfShow(id))
+		return nil
+	case utils.SpotifyLinkEpisode:
+		_ = open.Start(utils.WebURLOfEpisode(id))
+		return nil
+	}
+
+	if catched, page := m.HandleResCode(utils.CheckSpotifyErr(err), func() model.Page {
+		return openSpotifyLink(m, link)
+	}); catched {
+		return page
+	}
+	if err != nil {
+		utils.Logger().Printf("open link %s failed: %+v", link, err)
+		model.NewMenuTips(main, nil).DisplayTips(locale.MustT("open_link_failed"))
+	}
+	return nil
+}
+
+// playSongNext inserts the song after the playing one and plays it
+func playSongNext(m *Spotifox, song spotify.FullTrack) model.Page {
+	p := m.player
+	index := 0
+	if p.curSongIndex < len(p.playlist) {
+		index = p.curSongIndex + 1
+	}
+	playlist := make([]spotify.FullTrack, 0, len(p.playlist)+1)
+	playlist = append(playlist, p.playlist[:index]...)
+	playlist = append(playlist, song)
+	playlist = append(playlist, p.playlist[index:]...)
+
+	p.playlist = playlist
+	p.curSongIndex = index
+	p.playlistUpdateAt = time.Now()
+	return p.PlaySong(song, DurationNext)
+}
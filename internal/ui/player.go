@@ -6,6 +6,8 @@ import (
 	"math"
 	"math/rand"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/anhoder/foxful-cli/model"
@@ -64,7 +66,10 @@ type Player struct {
 	isCurSongLiked   bool
 	playingMenuKey   string
 	playingMenu      Menu
-	playedTime       time.Duration
+	playlistLoaded   atomic.Int32 // loaded count of playlist which is loading in background
+	playlistTotal    atomic.Int32
+	listening        lastfm.Listening // listened time of current song, for scrobbling
+	pendingSeek      time.Duration
 
 	lrcTimer          *lyric.LRCTimer
 	lyrics            [5]string
@@ -72,6 +77,9 @@ type Player struct {
 	lyricStartRow     int
 	lyricLines        int
 	lyricNowScrollBar *utils.XScrollBar
+	karaoke           atomic.Pointer[karaokeLine] // words of current line, nil if the line isn't enhanced lrc
+	trackLyricOffset  atomic.Int64                // time.Duration, read by the timer of lyrics
+	lyricOffsetAt     time.Time                   // when the offset is adjusted, shown for a while
 
 	progressLastWidth float64
 	progressRamp      []string
@@ -80,6 +88,12 @@ type Player struct {
 	mode         player.Mode
 	stateHandler *state_handler.Handler
 	ctrl         chan CtrlSignal
+	remote       remotePlayback
+
+	// ctx of the loops, which are stopped by Close
+	loopsL sync.Mutex
+	ctx    context.Context
+	loops  sync.WaitGroup
 
 	player.Player
 }
@@ -91,14 +105,19 @@ func NewPlayer(spotifox *Spotifox) *Player {
 		ctrl:              make(chan CtrlSignal),
 		lyricNowScrollBar: utils.NewXScrollBar(),
 	}
-	var ctx context.Context
-	ctx, p.cancel = context.WithCancel(context.Background())
+	p.start()
+	return p
+}
+
+// start creates the underlying player and runs the loops of it, which are stopped by Close
+func (p *Player) start() {
+	p.newLoopsContext()
 
 	p.Player = player.NewPlayerFromConfig()
 	p.stateHandler = state_handler.NewHandler(p, p.PlayingInfo())
 
 	// remote control
-	go utils.PanicRecoverWrapper(false, func() {
+	p.goLoop(func(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
@@ -109,7 +128,7 @@ func NewPlayer(spotifox *Spotifox) *Player {
 		}
 	})
 
-	go utils.PanicRecoverWrapper(false, func() {
+	p.goLoop(func(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
@@ -120,27 +139,32 @@ func NewPlayer(spotifox *Spotifox) *Player {
 					p.spotifox.Rerender(false)
 					break
 				}
-				// report to lastfm
-				lastfm.Report(p.spotifox.lastfm, lastfm.ReportPhaseComplete, p.curSong, p.PassedTime())
+				p.reportComplete()
 				_ = p.NextSong(false)
 			}
 		}
 	})
 
-	go utils.PanicRecoverWrapper(false, func() {
+	p.goLoop(func(ctx context.Context) {
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case duration := <-p.TimeChan():
-				p.playedTime += time.Millisecond * 200
+				p.listening.Observe(duration)
+				if p.pendingSeek > 0 {
+					// seek after the song is started, e.g. playback pulled back from another device
+					seekTo := p.pendingSeek
+					p.pendingSeek = 0
+					p.Seek(seekTo)
+				}
 				if duration.Seconds()-p.CurMusic().Duration().Seconds() > 10 {
-					lastfm.Report(p.spotifox.lastfm, lastfm.ReportPhaseComplete, p.curSong, p.PassedTime())
+					p.reportComplete()
 					_ = p.NextSong(false)
 				}
 				if p.lrcTimer != nil {
 					select {
-					case p.lrcTimer.Timer() <- duration + time.Millisecond*time.Duration(configs.ConfigRegistry.Main.LyricOffset):
+					case p.lrcTimer.Timer() <- duration + p.lyricOffset():
 					default:
 					}
 				}
@@ -150,7 +174,68 @@ func NewPlayer(spotifox *Spotifox) *Player {
 		}
 	})
 
-	return p
+	// the sung words are highlighted in a finer tick than TimeChan
+	p.goLoop(func(ctx context.Context) {
+		ticker := time.NewTicker(types.KaraokeTickDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				showLyric := p.showLyric || p.spotifox.CurPage().Type() == PageTypeLyrics
+				if showLyric && p.karaoke.Load() != nil && p.State() == player.Playing {
+					p.spotifox.Rerender(false)
+				}
+			}
+		}
+	})
+
+	// sync state of the remote device
+	p.goLoop(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		var lastSync time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !p.InRemoteMode() {
+					continue
+				}
+				if time.Since(lastSync) >= remoteSyncInterval {
+					p.syncRemoteState()
+					lastSync = time.Now()
+				}
+				p.spotifox.Rerender(false)
+			}
+		}
+	})
+}
+
+// newLoopsContext creates the context of loops started by start, canceled by Close
+func (p *Player) newLoopsContext() {
+	p.loopsL.Lock()
+	defer p.loopsL.Unlock()
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+}
+
+// goLoop runs f with the context of loops in a goroutine which is waited by StopLoops,
+// f isn't run if the loops have been stopped
+func (p *Player) goLoop(f func(ctx context.Context)) {
+	p.loopsL.Lock()
+	defer p.loopsL.Unlock()
+	// loops mustn't be added while StopLoops is waiting
+	if p.ctx.Err() != nil {
+		return
+	}
+	p.loops.Add(1)
+	ctx := p.ctx
+	go utils.PanicRecoverWrapper(false, func() {
+		defer p.loops.Done()
+		f(ctx)
+	})
 }
 
 func (p *Player) Update(_ tea.Msg, _ *model.App) {
@@ -171,6 +256,8 @@ func (p *Player) Update(_ tea.Msg, _ *model.App) {
 }
 
 func (p *Player) View(a *model.App, main *model.Main) (view string, lines int) {
+	p.spotifox.applyUITasks()
+
 	var playerBuilder strings.Builder
 	playerBuilder.WriteString(p.lyricView())
 	playerBuilder.WriteString(p.songView())
@@ -214,8 +301,7 @@ func (p *Player) lyricView() string {
 				lyricBuilder.WriteString(strings.Repeat(" ", startCol))
 			}
 			if i == 2 {
-				lyricLine := p.lyricNowScrollBar.Tick(maxLen, p.lyrics[i])
-				lyricBuilder.WriteString(util.SetFgStyle(lyricLine, termenv.ANSIBrightCyan))
+				lyricBuilder.WriteString(p.curLyricLineView(maxLen))
 			} else {
 				lyricLine := runewidth.Truncate(runewidth.FillRight(p.lyrics[i], maxLen), maxLen, "")
 				lyricBuilder.WriteString(util.SetFgStyle(lyricLine, termenv.ANSIBrightBlack))
@@ -230,8 +316,7 @@ func (p *Player) lyricView() string {
 				lyricBuilder.WriteString(strings.Repeat(" ", startCol))
 			}
 			if i == 2 {
-				lyricLine := p.lyricNowScrollBar.Tick(maxLen, p.lyrics[i])
-				lyricBuilder.WriteString(util.SetFgStyle(lyricLine, termenv.ANSIBrightCyan))
+				lyricBuilder.WriteString(p.curLyricLineView(maxLen))
 			} else {
 				lyricLine := runewidth.Truncate(runewidth.FillRight(p.lyrics[i], maxLen), maxLen, "")
 				lyricBuilder.WriteString(util.SetFgStyle(lyricLine, termenv.ANSIBrightBlack))
@@ -247,12 +332,53 @@ func (p *Player) lyricView() string {
 	return lyricBuilder.String()
 }
 
+// curLyricLineView renders the current line, the sung words of enhanced lrc are highlighted
+func (p *Player) curLyricLineView(maxLen int) string {
+	k := p.karaoke.Load()
+	if k == nil {
+		lyricLine := p.lyricNowScrollBar.Tick(maxLen, p.lyrics[len(p.lyrics)/2])
+		return util.SetFgStyle(lyricLine, termenv.ANSIBrightCyan)
+	}
+
+	var (
+		sung, unsung = k.split(p.PassedTime() + p.lyricOffset())
+		sungWidth    = runewidth.StringWidth(sung)
+		offset       int
+	)
+	unsung += k.suffix
+	// keep the sung position in the middle if the line is too long
+	if width := sungWidth + runewidth.StringWidth(unsung); width > maxLen {
+		offset = max(0, min(sungWidth-maxLen/2, width-maxLen))
+	}
+
+	sung, unsung = cutByWidth(sung, 0, offset, offset+maxLen), cutByWidth(unsung, sungWidth, offset, offset+maxLen)
+	unsung = runewidth.FillRight(unsung, maxLen-runewidth.StringWidth(sung))
+	return util.SetFgStyle(sung, termenv.ANSIBrightCyan) + util.SetFgStyle(unsung, termenv.ANSIWhite)
+}
+
+// cutByWidth keeps the runes of text located in [from, to) of the line, the text starts at column start
+func cutByWidth(text string, start, from, to int) string {
+	var builder strings.Builder
+	for _, r := range text {
+		w := runewidth.RuneWidth(r)
+		if start >= from && start+w <= to {
+			builder.WriteRune(r)
+		}
+		start += w
+	}
+	return builder.String()
+}
+
 func (p *Player) songView() string {
 	var (
 		builder strings.Builder
 		main    = p.spotifox.MustMain()
 	)
 
+	if device, state, _, ok := p.RemoteSnapshot(); ok {
+		return p.remoteSongView(device, state)
+	}
+
 	prefixLen := 10
 	if main.MenuStartColumn()-4 > 0 {
 		prefixLen += 12
@@ -260,6 +386,29 @@ func (p *Player) songView() string {
 		builder.WriteString(util.SetFgStyle(fmt.Sprintf("[%s] ", player.ModeName(p.mode)), termenv.ANSIBrightMagenta))
 		builder.WriteString(util.SetFgStyle(fmt.Sprintf("%d%% ", p.Volume()), termenv.ANSIBrightBlue))
 	}
+	if total := p.playlistTotal.Load(); total > 0 {
+		loadingTip := "[" + locale.MustT("loading_playlist", locale.WithTplData(map[string]int32{"Loaded": p.playlistLoaded.Load(), "Total": total})) + "] "
+		prefixLen += runewidth.StringWidth(loadingTip)
+		builder.WriteString(util.SetFgStyle(loadingTip, termenv.ANSIBrightBlack))
+	}
+	if status, ok := p.spotifox.httpRetry.Retrying(); ok {
+		retryingTip := "[" + locale.MustT("api_retrying", locale.WithTplData(map[string]any{
+			"Reason":  locale.MustT("retry_reason_" + string(status.Reason)),
+			"Seconds": max(int(time.Until(status.Until).Seconds()+0.5), 0),
+		})) + "] "
+		prefixLen += runewidth.StringWidth(retryingTip)
+		builder.WriteString(util.SetFgStyle(retryingTip, termenv.ANSIYellow))
+	}
+	if offsetTip := p.lyricOffsetTip(); offsetTip != "" {
+		offsetTip = "[" + offsetTip + "] "
+		prefixLen += runewidth.StringWidth(offsetTip)
+		builder.WriteString(util.SetFgStyle(offsetTip, termenv.ANSIBrightCyan))
+	}
+	if p.spotifox.httpCache.Stale() {
+		cachedTip := "[" + locale.MustT("cached") + "] "
+		prefixLen += runewidth.StringWidth(cachedTip)
+		builder.WriteString(util.SetFgStyle(cachedTip, termenv.ANSIBrightBlack))
+	}
 	if p.State() == player.Playing {
 		builder.WriteString(util.SetFgStyle("♫ ♪ ♫ ♪ ", termenv.ANSIBrightYellow))
 	} else {
@@ -300,10 +449,16 @@ func (p *Player) songView() string {
 
 func (p *Player) progressView() string {
 	allDuration := int(p.CurMusic().Duration().Seconds())
+	passedDuration := int(p.PassedTime().Seconds())
+	if _, state, progress, ok := p.RemoteSnapshot(); ok {
+		allDuration, passedDuration = 0, int(progress.Seconds())
+		if state != nil && state.Item != nil {
+			allDuration = int(state.Item.TimeDuration().Seconds())
+		}
+	}
 	if allDuration == 0 {
 		return ""
 	}
-	passedDuration := int(p.PassedTime().Seconds())
 	progress := passedDuration * 100 / allDuration
 
 	width := float64(p.spotifox.WindowWidth() - 14)
@@ -377,6 +532,28 @@ func (p *Player) LocatePlayingSong() {
 	main.SetSelectedIndex(p.curSongIndex)
 }
 
+// LoadFullPlaylist loads remaining songs of the menu in background,
+// and replaces the playlist with the complete one if the menu is still playing.
+func (p *Player) LoadFullPlaylist(menu FullLoadableMenu) {
+	menuKey := menu.GetMenuKey()
+	menu.LoadAllSongs(func(loaded, total int) {
+		p.playlistLoaded.Store(int32(loaded))
+		p.playlistTotal.Store(int32(total))
+		p.spotifox.Rerender(false)
+	}, func(songs []spotify.FullTrack) {
+		// on the UI goroutine
+		p.playlistLoaded.Store(0)
+		p.playlistTotal.Store(0)
+		if songs != nil && p.playingMenuKey == menuKey && len(songs) >= len(p.playlist) {
+			newPlaylist := make([]spotify.FullTrack, len(songs))
+			copy(newPlaylist, songs)
+			p.playlist = newPlaylist
+			p.playlistUpdateAt = time.Now()
+		}
+		p.spotifox.MustMain().RefreshMenuList()
+	})
+}
+
 func (p *Player) PlaySong(song spotify.FullTrack, direction PlayDirection) model.Page {
 	if p.spotifox.CheckAuthSession() == utils.NeedLogin {
 		page, _ := p.spotifox.ToLoginPage(func() model.Page {
@@ -390,6 +567,13 @@ func (p *Player) PlaySong(song spotify.FullTrack, direction PlayDirection) model
 	loading.Start()
 	defer loading.Complete()
 
+	if p.InRemoteMode() {
+		p.curSong = song
+		p.LocatePlayingSong()
+		p.remotePlaySong(song)
+		return nil
+	}
+
 	p.isCurSongLiked = p.spotifox.CheckLikedSong(song.ID)
 
 	table := storage.NewTable()
@@ -399,16 +583,19 @@ func (p *Player) PlaySong(song spotify.FullTrack, direction PlayDirection) model
 		PlaylistUpdateAt: p.playlistUpdateAt,
 		IsCurSongLiked:   p.isCurSongLiked,
 	})
+	// the skipped song may be listened enough
+	p.reportComplete()
 	p.curSong = song
-	p.playedTime = 0
+	// the offset applies even if the lyrics aren't shown, so that it's right when they're shown
+	p.trackLyricOffset.Store(int64(storage.LyricOffset{TrackId: song.ID.String()}.Load()))
 
 	p.LocatePlayingSong()
 	p.Player.Paused()
 
 	var asset arc.MediaAsset
-	err := p.spotifox.ReconnSessionWhenNeed(func() error {
+	err := p.spotifox.ReconnSessionWhenNeed(func(sess respot.Session) error {
 		var err error
-		asset, err = p.spotifox.sess.PinTrack(string(song.ID), respot.PinOpts{})
+		asset, err = sess.PinTrack(string(song.ID), respot.PinOpts{})
 		return err
 	})
 	if err != nil {
@@ -428,7 +615,7 @@ func (p *Player) PlaySong(song spotify.FullTrack, direction PlayDirection) model
 	}
 
 	if configs.ConfigRegistry.Main.ShowLyric {
-		go p.updateLyric(song.ID)
+		p.goLoop(func(ctx context.Context) { p.updateLyric(ctx, song) })
 	}
 
 	p.Player.Play(player.MediaAsset{
@@ -436,7 +623,9 @@ func (p *Player) PlaySong(song spotify.FullTrack, direction PlayDirection) model
 		SongInfo:   song,
 	})
 
-	lastfm.Report(p.spotifox.lastfm, lastfm.ReportPhaseStart, p.curSong, p.PassedTime())
+	startedAt := time.Now()
+	p.listening.Start(song, startedAt)
+	lastfm.Report(p.spotifox.lastfm, lastfm.ReportPhaseStart, song, 0, startedAt)
 
 	go utils.Notify(utils.NotifyContent{
 		Title:   locale.MustT("now_playing", locale.WithTplData(map[string]string{"TrackName": song.Name})),
@@ -556,12 +745,20 @@ func (p *Player) PreviousSong(isManual bool) model.Page {
 
 func (p *Player) Seek(duration time.Duration) {
 	p.Player.Seek(duration)
+	p.listening.Seeked(duration)
 	if p.lrcTimer != nil {
 		p.lrcTimer.Rewind()
 	}
 	p.stateHandler.SetPlayingInfo(p.PlayingInfo())
 }
 
+// reportComplete scrobbles current song once if it's listened enough
+func (p *Player) reportComplete() {
+	if song, listened, startedAt, ok := p.listening.Complete(); ok {
+		lastfm.Report(p.spotifox.lastfm, lastfm.ReportPhaseComplete, song, listened, startedAt)
+	}
+}
+
 func (p *Player) SetPlayMode(playMode player.Mode) {
 	if playMode > 0 {
 		p.mode = playMode
@@ -588,12 +785,50 @@ func (p *Player) Close() {
 	p.Player.Close()
 }
 
-func (p *Player) lyricListener(_ int64, content, transContent string, _ bool, index int) {
+// StopLoops stops the loops of player and waits for them. It mustn't be called on the UI goroutine,
+// which the loops may be waiting for to rerender
+func (p *Player) StopLoops() {
+	p.loopsL.Lock()
+	p.cancel()
+	p.loopsL.Unlock()
+	p.loops.Wait()
+}
+
+// Reset stops the playback and clears the playlist, e.g. when switching profile.
+// StopLoops must be called before, so that the state isn't modified by the loops while clearing
+func (p *Player) Reset() {
+	p.Close()
+	p.lrcTimer = nil
+	p.ExitRemoteMode()
+
+	p.playlist, p.playlistUpdateAt = nil, time.Time{}
+	p.curSongIndex, p.curSong, p.isCurSongLiked = 0, spotify.FullTrack{}, false
+	p.playingMenuKey, p.playingMenu = "", nil
+	p.playlistLoaded.Store(0)
+	p.playlistTotal.Store(0)
+	p.listening.Reset()
+	p.pendingSeek = 0
+	p.lyrics, p.lyricOffsetAt = [5]string{}, time.Time{}
+	p.trackLyricOffset.Store(0)
+	p.karaoke.Store(nil)
+	p.playErrCount = 0
+	p.mode = player.PmListLoop
+	p.start()
+}
+
+// lyricListener updates the lyrics shown with the fragments of timer
+func (p *Player) lyricListener(timer *lyric.LRCTimer) lyric.Listener {
+	return func(_ int64, content, transContent string, _ bool, index int) {
+		p.updateLyricLines(timer, content, transContent, index)
+	}
+}
+
+func (p *Player) updateLyricLines(timer *lyric.LRCTimer, content, transContent string, index int) {
 	curIndex := len(p.lyrics) / 2
 
 	// before
 	for i := 0; i < curIndex; i++ {
-		if f, tf := p.lrcTimer.GetLRCFragment(index - curIndex + i); f != nil {
+		if f, tf := timer.GetLRCFragment(index - curIndex + i); f != nil {
 			p.lyrics[i] = f.Content
 			if tf != nil && tf.Content != "" {
 				p.lyrics[i] += " [" + tf.Content + "]"
@@ -608,10 +843,21 @@ func (p *Player) lyricListener(_ int64, content, transContent string, _ bool, in
 	if transContent != "" {
 		p.lyrics[curIndex] += " [" + transContent + "]"
 	}
+	var k *karaokeLine
+	if f, _ := timer.GetLRCFragment(index); f != nil && len(f.Words) > 0 {
+		k = &karaokeLine{words: f.Words, endMs: p.CurMusic().Duration().Milliseconds()}
+		if next, _ := timer.GetLRCFragment(index + 1); next != nil {
+			k.endMs = next.StartTimeMs
+		}
+		if transContent != "" {
+			k.suffix = " [" + transContent + "]"
+		}
+	}
+	p.karaoke.Store(k)
 
 	// after
 	for i := 1; i < len(p.lyrics)-curIndex; i++ {
-		if f, tf := p.lrcTimer.GetLRCFragment(index + i); f != nil {
+		if f, tf := timer.GetLRCFragment(index + i); f != nil {
 			p.lyrics[curIndex+i] = f.Content
 			if tf != nil && tf.Content != "" {
 				p.lyrics[curIndex+i] += " [" + tf.Content + "]"
@@ -622,21 +868,56 @@ func (p *Player) lyricListener(_ int64, content, transContent string, _ bool, in
 	}
 }
 
-func (p *Player) updateLyric(songId spotify.ID) {
+// lyricOffset the configured offset of lyrics, adjusted for current track
+func (p *Player) lyricOffset() time.Duration {
+	return time.Millisecond*time.Duration(configs.ConfigRegistry.Main.LyricOffset) + time.Duration(p.trackLyricOffset.Load())
+}
+
+// AdjustLyricOffset adjusts the offset of lyrics for current track, returns the adjusted offset
+func (p *Player) AdjustLyricOffset(delta time.Duration) time.Duration {
+	offset := storage.LyricOffset{TrackId: p.curSong.ID.String()}.Adjust(time.Duration(p.trackLyricOffset.Load()), delta)
+	p.trackLyricOffset.Store(int64(offset))
+	p.lyricOffsetAt = time.Now()
+	// locate the line again, the timer only moves forward
+	if p.lrcTimer != nil {
+		p.lrcTimer.Rewind()
+	}
+	return offset
+}
+
+// lyricOffsetTip the offset of current track, empty if it isn't adjusted just now
+func (p *Player) lyricOffsetTip() string {
+	if time.Since(p.lyricOffsetAt) >= types.LyricOffsetTipsDuration {
+		return ""
+	}
+	return locale.MustT("lyric_offset", locale.WithTplData(map[string]string{
+		"Offset": fmt.Sprintf("%+dms", time.Duration(p.trackLyricOffset.Load()).Milliseconds()),
+	}))
+}
+
+// updateLyric fetches the lyrics and runs the timer of them until the next song or ctx is done
+func (p *Player) updateLyric(ctx context.Context, song spotify.FullTrack) {
 	p.lyrics = [5]string{}
+	p.karaoke.Store(nil)
 	if p.lrcTimer != nil {
 		p.lrcTimer.Stop()
 	}
 	lrcFile, _ := lyric.ReadLRC(strings.NewReader("[00:00.00] No Lyrics~"))
 	tranLRCFile, _ := lyric.ReadTranslateLRC(strings.NewReader("[00:00.00]"))
 	defer func() {
-		p.lrcTimer = lyric.NewLRCTimer(lrcFile, tranLRCFile)
-		p.lrcTimer.AddListener(p.lyricListener)
-		p.lrcTimer.Start()
+		timer := lyric.NewLRCTimer(lrcFile, tranLRCFile)
+		timer.AddListener(p.lyricListener(timer))
+		p.lrcTimer = timer
+		stop := context.AfterFunc(ctx, timer.Stop)
+		defer stop()
+		timer.Start()
 	}()
 
-	if l := p.spotifox.FetchSongLyrics(songId); l != nil {
+	if l, tl := p.spotifox.FetchSongLyrics(song); l != nil {
 		lrcFile = l
+		if configs.ConfigRegistry.Main.ShowLyricTrans {
+			tranLRCFile = tl
+		}
 	}
 }
 
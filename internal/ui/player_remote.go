@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anhoder/foxful-cli/util"
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	"github.com/zmb3/spotify/v2"
+)
+
+const (
+	remoteSyncInterval = time.Second * 5
+	remoteVolumeStep   = 10
+)
+
+// remotePlayback is the playback of another Connect device, spotifox acts as a remote of it
+type remotePlayback struct {
+	l      sync.RWMutex
+	device *spotify.PlayerDevice
+	state  *spotify.PlayerState
+	syncAt time.Time
+}
+
+func (p *Player) InRemoteMode() bool {
+	p.remote.l.RLock()
+	defer p.remote.l.RUnlock()
+	return p.remote.device != nil
+}
+
+// EnterRemoteMode pauses local playback and follows the playback of the device
+func (p *Player) EnterRemoteMode(device spotify.PlayerDevice) {
+	p.Player.Paused()
+
+	p.remote.l.Lock()
+	p.remote.device = &device
+	p.remote.state = nil
+	p.remote.l.Unlock()
+
+	p.syncRemoteState()
+}
+
+func (p *Player) ExitRemoteMode() {
+	p.remote.l.Lock()
+	defer p.remote.l.Unlock()
+	p.remote.device = nil
+	p.remote.state = nil
+}
+
+// RemoteSnapshot returns the followed device, its last known state and the estimated progress
+func (p *Player) RemoteSnapshot() (device spotify.PlayerDevice, state *spotify.PlayerState, progress time.Duration, ok bool) {
+	p.remote.l.RLock()
+	defer p.remote.l.RUnlock()
+	if p.remote.device == nil {
+		return
+	}
+	device, state, ok = *p.remote.device, p.remote.state, true
+	if state == nil {
+		return
+	}
+	progress = time.Duration(state.Progress) * time.Millisecond
+	if state.Playing {
+		progress += time.Since(p.remote.syncAt)
+	}
+	if state.Item != nil && progress > state.Item.TimeDuration() {
+		progress = state.Item.TimeDuration()
+	}
+	return
+}
+
+func (p *Player) syncRemoteState() {
+	if !p.InRemoteMode() || p.spotifox.connect == nil {
+		return
+	}
+	state, err := p.spotifox.connect.State(context.Background())
+	if err != nil {
+		utils.Logger().Printf("sync remote state failed: %+v", err)
+		return
+	}
+
+	p.remote.l.Lock()
+	defer p.remote.l.Unlock()
+	if p.remote.device == nil {
+		return
+	}
+	p.remote.state, p.remote.syncAt = state, time.Now()
+	// refresh volume, and playback may be moved to another device by other clients
+	if state != nil {
+		device := state.Device
+		p.remote.device = &device
+	}
+}
+
+func (p *Player) remoteDeviceId() (spotify.ID, bool) {
+	p.remote.l.RLock()
+	defer p.remote.l.RUnlock()
+	if p.remote.device == nil || p.spotifox.connect == nil {
+		return "", false
+	}
+	return p.remote.device.ID, true
+}
+
+// remoteCtrl calls the Web API for the followed device and refreshes its state
+func (p *Player) remoteCtrl(f func(ctx context.Context, deviceId spotify.ID) error) {
+	deviceId, ok := p.remoteDeviceId()
+	if !ok {
+		return
+	}
+	if err := f(context.Background(), deviceId); err != nil {
+		utils.Logger().Printf("remote control failed: %+v", err)
+		return
+	}
+	// wait for the device to apply the change
+	time.Sleep(time.Millisecond * 300)
+	p.syncRemoteState()
+	p.spotifox.Rerender(false)
+}
+
+func (p *Player) RemoteToggle() {
+	_, state, _, _ := p.RemoteSnapshot()
+	if state != nil && state.Playing {
+		p.remoteCtrl(p.spotifox.connect.Pause)
+		return
+	}
+	p.remoteCtrl(p.spotifox.connect.Play)
+}
+
+func (p *Player) RemoteNext() {
+	p.remoteCtrl(p.spotifox.connect.Next)
+}
+
+func (p *Player) RemotePrevious() {
+	p.remoteCtrl(p.spotifox.connect.Previous)
+}
+
+// RemoteSeek seeks the followed device to position
+func (p *Player) RemoteSeek(position time.Duration) {
+	p.remoteCtrl(func(ctx context.Context, deviceId spotify.ID) error {
+		return p.spotifox.connect.Seek(ctx, deviceId, position)
+	})
+}
+
+// RemoteSeekBy seeks the followed device by delta from the estimated progress
+func (p *Player) RemoteSeekBy(delta time.Duration) {
+	_, _, progress, ok := p.RemoteSnapshot()
+	if !ok {
+		return
+	}
+	p.RemoteSeek(progress + delta)
+}
+
+func (p *Player) RemoteUpVolume() {
+	p.remoteChangeVolume(remoteVolumeStep)
+}
+
+func (p *Player) RemoteDownVolume() {
+	p.remoteChangeVolume(-remoteVolumeStep)
+}
+
+func (p *Player) remoteChangeVolume(delta int) {
+	device, _, _, ok := p.RemoteSnapshot()
+	if !ok {
+		return
+	}
+	p.remoteCtrl(func(ctx context.Context, deviceId spotify.ID) error {
+		return p.spotifox.connect.SetVolume(ctx, deviceId, device.Volume+delta)
+	})
+}
+
+// remotePlaySong plays the song on the followed device instead of spotifox
+func (p *Player) remotePlaySong(song spotify.FullTrack) {
+	p.remoteCtrl(func(ctx context.Context, deviceId spotify.ID) error {
+		return p.spotifox.connect.TransferTo(ctx, deviceId, song.ID, 0)
+	})
+}
+
+func (p *Player) remoteSongView(device spotify.PlayerDevice, state *spotify.PlayerState) string {
+	var (
+		builder strings.Builder
+		main    = p.spotifox.MustMain()
+	)
+
+	deviceName := locale.MustT("remote_device", locale.WithTplData(map[string]string{"DeviceName": device.Name}))
+	prefixLen := 10 + runewidth.StringWidth(deviceName) + 3
+	if main.MenuStartColumn()-4 > 0 {
+		prefixLen += 5
+		builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()-4))
+	}
+	builder.WriteString(util.SetFgStyle("["+deviceName+"] ", termenv.ANSIBrightMagenta))
+	if main.MenuStartColumn()-4 > 0 {
+		builder.WriteString(util.SetFgStyle(fmt.Sprintf("%d%% ", device.Volume), termenv.ANSIBrightBlue))
+	}
+	if state != nil && state.Playing {
+		builder.WriteString(util.SetFgStyle("♫ ♪ ♫ ♪ ", termenv.ANSIBrightYellow))
+	} else {
+		builder.WriteString(util.SetFgStyle("_ z Z Z ", termenv.ANSIYellow))
+	}
+
+	if state == nil || state.Item == nil {
+		return builder.String()
+	}
+
+	song := state.Item
+	truncateSong := runewidth.Truncate(song.Name, p.spotifox.WindowWidth()-main.MenuStartColumn()-prefixLen, "")
+	builder.WriteString(util.SetFgStyle(truncateSong, util.GetPrimaryColor()))
+	builder.WriteString(" ")
+
+	remainLen := p.spotifox.WindowWidth() - main.MenuStartColumn() - prefixLen - runewidth.StringWidth(song.Name)
+	truncateArtists := runewidth.Truncate(
+		runewidth.FillRight(utils.ArtistNameStrOfSong(song), remainLen),
+		remainLen, "")
+	builder.WriteString(util.SetFgStyle(truncateArtists, termenv.ANSIBrightBlack))
+
+	return builder.String()
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -11,7 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-musicfox/spotifox/internal/configs"
-	"github.com/go-musicfox/spotifox/internal/types"
+	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
 	"github.com/mattn/go-runewidth"
@@ -21,6 +22,12 @@ import (
 
 const PageTypeSearch model.PageType = "search"
 
+const (
+	searchCharLimit    = 256
+	searchHistoryLimit = 50
+	searchHistoryShown = 5
+)
+
 type tickSearchMsg struct{}
 
 func tickSearch(duration time.Duration) tea.Cmd {
@@ -29,30 +36,60 @@ func tickSearch(duration time.Duration) tea.Cmd {
 	})
 }
 
+// inputs of search page, the first is free text, the others build field filters
+const (
+	searchInputKeyword = iota
+	searchInputArtist
+	searchInputAlbum
+	searchInputYearFrom
+	searchInputYearTo
+	searchInputGenre
+	searchInputSaveAs
+)
+
+var searchInputPlaceholders = []string{
+	searchInputKeyword:  "input_keyword",
+	searchInputArtist:   "search_filter_artist",
+	searchInputAlbum:    "search_filter_album",
+	searchInputYearFrom: "search_filter_year_from",
+	searchInputYearTo:   "search_filter_year_to",
+	searchInputGenre:    "search_filter_genre",
+	searchInputSaveAs:   "search_save_as",
+}
+
 type SearchPage struct {
 	spotifox  *Spotifox
 	menuTitle *model.MenuItem
 
 	index        int
-	wordsInput   textinput.Model
+	inputs       []textinput.Model
 	submitButton string
 	tips         string
-	searchType   spotify.SearchType
-	result       interface{}
+
+	keyword string
+	result  *spotify.SearchResult
+
+	history      []string
+	historyIndex int // -1 means the input is not recalled from history
+	draft        string
 }
 
 func NewSearchPage(netease *Spotifox) (search *SearchPage) {
 	search = &SearchPage{
 		spotifox:     netease,
 		menuTitle:    &model.MenuItem{Title: locale.MustT("search")},
-		wordsInput:   textinput.New(),
 		submitButton: model.GetBlurredSubmitButton(),
+		historyIndex: -1,
 	}
-	search.wordsInput.Placeholder = " " + locale.MustT("input_keyword")
-	search.wordsInput.Focus()
-	search.wordsInput.Prompt = model.GetFocusedPrompt()
-	search.wordsInput.TextStyle = util.GetPrimaryFontStyle()
-	search.wordsInput.CharLimit = 32
+	for _, placeholder := range searchInputPlaceholders {
+		input := textinput.New()
+		input.Placeholder = " " + locale.MustT(placeholder)
+		input.CharLimit = searchCharLimit
+		search.inputs = append(search.inputs, input)
+	}
+	search.inputs[searchInputYearFrom].CharLimit = 4
+	search.inputs[searchInputYearTo].CharLimit = 4
+	search.focusInput(searchInputKeyword)
 	return
 }
 
@@ -79,21 +116,21 @@ func (s *SearchPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 		s.Reset()
 		return s.spotifox.MustMain(), s.spotifox.RerenderCmd(true)
 
-	// Cycle between inputs
-	case "tab", "shift+tab", "enter", "up", "down":
-		if s.searchType == 0 {
+	case "up", "down":
+		if s.index == searchInputKeyword {
+			s.recallHistory(key.String() == "up")
 			return s, nil
 		}
+		fallthrough
 
-		inputs := []textinput.Model{
-			s.wordsInput,
-		}
+	// Cycle between inputs
+	case "tab", "shift+tab", "enter":
+		inputs := s.inputs
 
 		k := key.String()
 
-		// Did the user press enter while the submit button was focused?
-		// If so, exit.
-		if k == "enter" && s.index == len(inputs) {
+		// Submit both on the input and the submit button
+		if k == "enter" {
 			return s.enterHandler()
 		}
 
@@ -110,27 +147,7 @@ func (s *SearchPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 			s.index = len(inputs)
 		}
 
-		for i := 0; i <= len(inputs)-1; i++ {
-			if i == s.index {
-				// Set focused state
-				inputs[i].Focus()
-				inputs[i].Prompt = model.GetFocusedPrompt()
-				inputs[i].TextStyle = util.GetPrimaryFontStyle()
-				continue
-			}
-			// Remove focused state
-			inputs[i].Blur()
-			inputs[i].Prompt = model.GetBlurredPrompt()
-			inputs[i].TextStyle = lipgloss.NewStyle()
-		}
-
-		s.wordsInput = inputs[0]
-		if s.index == len(inputs) {
-			s.submitButton = model.GetFocusedSubmitButton()
-		} else {
-			s.submitButton = model.GetBlurredSubmitButton()
-		}
-
+		s.focusInput(s.index)
 		return s, nil
 	}
 
@@ -139,8 +156,9 @@ func (s *SearchPage) Update(msg tea.Msg, _ *model.App) (model.Page, tea.Cmd) {
 }
 
 func (s *SearchPage) enterHandler() (model.Page, tea.Cmd) {
-	if len(s.wordsInput.Value()) <= 0 {
-		s.tips = util.SetFgStyle(locale.MustT("keyword_cannot_be_empty"), termenv.ANSIBrightRed)
+	query := s.query()
+	if err := query.Validate(); err != nil {
+		s.tips = util.SetFgStyle(err.Error(), termenv.ANSIBrightRed)
 		return s, nil
 	}
 	loading := model.NewLoading(s.spotifox.MustMain(), s.menuTitle)
@@ -156,7 +174,8 @@ func (s *SearchPage) enterHandler() (model.Page, tea.Cmd) {
 		return page, func() tea.Msg { return page.Msg() }
 	}
 
-	res, err := s.spotifox.spotifyClient.Search(context.Background(), s.wordsInput.Value(), s.searchType, spotify.Limit(types.SearchPageSize))
+	keyword := query.String()
+	res, err := s.spotifox.spotifyClient.Search(context.Background(), keyword, searchAllTypes, s.spotifox.searchOptions(0)...)
 	if catched, page := s.spotifox.HandleResCode(utils.CheckSpotifyErr(err), func() model.Page {
 		s.enterHandler()
 		return nil
@@ -168,24 +187,13 @@ func (s *SearchPage) enterHandler() (model.Page, tea.Cmd) {
 		return nil, nil
 	}
 
-	switch s.searchType {
-	case spotify.SearchTypeTrack:
-		s.result = res.Tracks.Tracks
-	case spotify.SearchTypeAlbum:
-		s.result = res.Albums.Albums
-	case spotify.SearchTypeArtist:
-		var artists []spotify.SimpleArtist
-		for _, artist := range res.Artists.Artists {
-			artists = append(artists, artist.SimpleArtist)
-		}
-		s.result = artists
-	case spotify.SearchTypePlaylist:
-		s.result = res.Playlists.Playlists
-	case spotify.SearchTypeShow:
-		s.result = res.Shows.Shows
-	case spotify.SearchTypeEpisode:
-		s.result = res.Episodes.Episodes
+	s.addHistory(keyword)
+	if name := strings.TrimSpace(s.inputs[searchInputSaveAs].Value()); name != "" {
+		s.spotifox.SaveSearch(name, keyword)
+		// saved searches are listed after search, so selected index keeps pointing to search
+		s.spotifox.MustMain().RefreshMenuList()
 	}
+	s.keyword, s.result = keyword, res
 	s.spotifox.MustMain().EnterMenu(nil, nil)
 
 	s.Reset()
@@ -207,18 +215,11 @@ func (s *SearchPage) View(a *model.App) string {
 	}
 
 	// menu title
-	menuViews := main.CurMenu().MenuViews()
-	if main.SelectedIndex() < len(menuViews) {
-		typeMenu := menuViews[main.SelectedIndex()]
-		s.menuTitle.Subtitle = typeMenu.Title
-	}
 	builder.WriteString(main.MenuTitleView(a, &top, s.menuTitle))
 	builder.WriteString("\n\n\n")
 	top += 2
 
-	inputs := []textinput.Model{
-		s.wordsInput,
-	}
+	inputs := s.inputs
 
 	for i, input := range inputs {
 		if main.MenuStartColumn() > 0 {
@@ -239,12 +240,23 @@ func (s *SearchPage) View(a *model.App) string {
 
 		top++
 
-		if i < len(inputs)-1 {
+		// free text and save name are separated from filters
+		if i == searchInputKeyword || i == searchInputSaveAs-1 {
 			builder.WriteString("\n\n")
 			top++
+		} else if i < len(inputs)-1 {
+			builder.WriteString("\n")
 		}
 	}
 
+	// preview of the query to send
+	builder.WriteString("\n\n")
+	top++
+	if main.MenuStartColumn() > 0 {
+		builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()))
+	}
+	builder.WriteString(s.previewView(a.WindowWidth() - main.MenuStartColumn()))
+
 	builder.WriteString("\n\n")
 	top++
 	if main.MenuStartColumn() > 0 {
@@ -253,6 +265,14 @@ func (s *SearchPage) View(a *model.App) string {
 	builder.WriteString(s.tips)
 	builder.WriteString("\n\n")
 	top++
+	if historyView := s.historyView(a.WindowWidth() - main.MenuStartColumn()); historyView != "" {
+		if main.MenuStartColumn() > 0 {
+			builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()))
+		}
+		builder.WriteString(historyView)
+		builder.WriteString("\n\n")
+		top += 2
+	}
 	if main.MenuStartColumn() > 0 {
 		builder.WriteString(strings.Repeat(" ", main.MenuStartColumn()))
 	}
@@ -276,14 +296,93 @@ func (s *SearchPage) Msg() tea.Msg {
 
 func (s *SearchPage) Reset() {
 	s.tips = ""
-	s.wordsInput.SetValue("")
-	s.wordsInput.Reset()
-	s.index = 0
-	s.wordsInput.Focus()
-	s.wordsInput.Prompt = model.GetFocusedPrompt()
-	s.wordsInput.TextStyle = util.GetPrimaryFontStyle()
-	s.wordsInput.CharLimit = 32
-	s.submitButton = model.GetBlurredSubmitButton()
+	for i := range s.inputs {
+		s.inputs[i].SetValue("")
+		s.inputs[i].Reset()
+	}
+	s.focusInput(searchInputKeyword)
+	s.historyIndex = -1
+	s.draft = ""
+}
+
+// takeResult returns the result of last search, and clears it
+func (s *SearchPage) takeResult() (string, *spotify.SearchResult) {
+	keyword, result := s.keyword, s.result
+	s.keyword, s.result = "", nil
+	return keyword, result
+}
+
+func (s *SearchPage) loadHistory() {
+	table := storage.NewTable()
+	if jsonStr, err := table.GetByKVModel(storage.SearchHistory{}); err == nil && len(jsonStr) > 0 {
+		_ = json.Unmarshal(jsonStr, &s.history)
+	}
+}
+
+func (s *SearchPage) addHistory(keyword string) {
+	history := []string{keyword}
+	for _, h := range s.history {
+		if h != keyword && len(history) < searchHistoryLimit {
+			history = append(history, h)
+		}
+	}
+	s.history = history
+
+	table := storage.NewTable()
+	_ = table.SetByKVModel(storage.SearchHistory{}, s.history)
+}
+
+// recallHistory fills the input with the previous(older) or next(newer) keyword in history
+func (s *SearchPage) recallHistory(older bool) {
+	index := s.historyIndex
+	if older {
+		index++
+	} else {
+		index--
+	}
+	if index < -1 || index >= len(s.history) {
+		return
+	}
+	input := &s.inputs[searchInputKeyword]
+	if s.historyIndex == -1 {
+		s.draft = input.Value()
+	}
+	s.historyIndex = index
+	if index == -1 {
+		input.SetValue(s.draft)
+	} else {
+		input.SetValue(s.history[index])
+	}
+	input.CursorEnd()
+}
+
+func (s *SearchPage) historyView(maxWidth int) string {
+	if len(s.history) == 0 || maxWidth <= 0 {
+		return ""
+	}
+	var (
+		builder strings.Builder
+		width   int
+	)
+	title := locale.MustT("search_history") + ": "
+	builder.WriteString(util.SetFgStyle(title, termenv.ANSIBrightBlack))
+	width += runewidth.StringWidth(title)
+	for i := 0; i < len(s.history) && i < searchHistoryShown; i++ {
+		item := s.history[i]
+		if i > 0 {
+			item = " | " + item
+		}
+		if width+runewidth.StringWidth(item) > maxWidth {
+			break
+		}
+		width += runewidth.StringWidth(item)
+		if i == s.historyIndex {
+			builder.WriteString(util.SetFgStyle(item, util.GetPrimaryColor()))
+		} else {
+			builder.WriteString(util.SetFgStyle(item, termenv.ANSIBrightBlack))
+		}
+	}
+	return builder.String()
 }
 
 func (s *SearchPage) updateSearchInputs(msg tea.Msg) (model.Page, tea.Cmd) {
@@ -291,7 +390,60 @@ func (s *SearchPage) updateSearchInputs(msg tea.Msg) (model.Page, tea.Cmd) {
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
-	s.wordsInput, cmd = s.wordsInput.Update(msg)
+	if s.index >= len(s.inputs) {
+		return s, nil
+	}
+	value := s.inputs[s.index].Value()
+	s.inputs[s.index], cmd = s.inputs[s.index].Update(msg)
 	cmds = append(cmds, cmd)
+	if s.index == searchInputKeyword && s.inputs[s.index].Value() != value {
+		// edited, stop recalling
+		s.historyIndex = -1
+	}
 	return s, tea.Batch(cmds...)
 }
+
+// focusInput focuses the input at index, or the submit button if index is out of inputs
+func (s *SearchPage) focusInput(index int) {
+	s.index = index
+	for i := range s.inputs {
+		if i == index {
+			s.inputs[i].Focus()
+			s.inputs[i].Prompt = model.GetFocusedPrompt()
+			s.inputs[i].TextStyle = util.GetPrimaryFontStyle()
+			continue
+		}
+		s.inputs[i].Blur()
+		s.inputs[i].Prompt = model.GetBlurredPrompt()
+		s.inputs[i].TextStyle = lipgloss.NewStyle()
+	}
+	if index == len(s.inputs) {
+		s.submitButton = model.GetFocusedSubmitButton()
+	} else {
+		s.submitButton = model.GetBlurredSubmitButton()
+	}
+}
+
+func (s *SearchPage) query() searchQuery {
+	return searchQuery{
+		keyword:  s.inputs[searchInputKeyword].Value(),
+		artist:   s.inputs[searchInputArtist].Value(),
+		album:    s.inputs[searchInputAlbum].Value(),
+		yearFrom: s.inputs[searchInputYearFrom].Value(),
+		yearTo:   s.inputs[searchInputYearTo].Value(),
+		genre:    s.inputs[searchInputGenre].Value(),
+	}
+}
+
+func (s *SearchPage) previewView(maxWidth int) string {
+	query := s.query()
+	if query.String() == "" {
+		return ""
+	}
+	title := locale.MustT("search_query_preview") + ": "
+	if err := query.Validate(); err != nil {
+		return util.SetFgStyle(runewidth.Truncate(title+err.Error(), maxWidth, ""), termenv.ANSIBrightRed)
+	}
+	return util.SetFgStyle(title, termenv.ANSIBrightBlack) +
+		util.SetFgStyle(runewidth.Truncate(query.String(), maxWidth-runewidth.StringWidth(title), "…"), util.GetPrimaryColor())
+}
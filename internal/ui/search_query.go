@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/pkg/errors"
+)
+
+var (
+	searchYearRegex   = regexp.MustCompile(`^\d{4}$`)
+	searchFilterRegex = regexp.MustCompile(`(?i)\b(year|tag):(\S*)`)
+)
+
+// searchQuery free text with field filters of Spotify search, e.g. `love artist:"Daft Punk" year:2000-2009`
+type searchQuery struct {
+	keyword  string
+	artist   string
+	album    string
+	yearFrom string
+	yearTo   string
+	genre    string
+}
+
+func (q searchQuery) String() string {
+	var parts []string
+	if keyword := strings.TrimSpace(q.keyword); keyword != "" {
+		parts = append(parts, keyword)
+	}
+	parts = appendSearchFilter(parts, "artist", q.artist)
+	parts = appendSearchFilter(parts, "album", q.album)
+	yearFrom, yearTo := strings.TrimSpace(q.yearFrom), strings.TrimSpace(q.yearTo)
+	switch {
+	case yearFrom != "" && yearTo != "" && yearFrom != yearTo:
+		parts = append(parts, "year:"+yearFrom+"-"+yearTo)
+	case yearFrom != "":
+		parts = append(parts, "year:"+yearFrom)
+	case yearTo != "":
+		parts = append(parts, "year:"+yearTo)
+	}
+	parts = appendSearchFilter(parts, "genre", q.genre)
+	return strings.Join(parts, " ")
+}
+
+func appendSearchFilter(parts []string, field, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return parts
+	}
+	if strings.ContainsAny(value, " \t") {
+		value = `"` + value + `"`
+	}
+	return append(parts, field+":"+value)
+}
+
+// Validate checks fields of builder and the filters typed in free text
+func (q searchQuery) Validate() error {
+	if q.String() == "" {
+		return errors.New(locale.MustT("keyword_cannot_be_empty"))
+	}
+	for _, v := range []string{q.artist, q.album, q.genre} {
+		if strings.Contains(v, `"`) {
+			return errors.New(locale.MustT("search_filter_invalid_quote"))
+		}
+	}
+
+	var years []int
+	for _, y := range []string{q.yearFrom, q.yearTo} {
+		y = strings.TrimSpace(y)
+		if y == "" {
+			continue
+		}
+		year, err := parseSearchYear(y)
+		if err != nil {
+			return err
+		}
+		years = append(years, year)
+	}
+	if len(years) == 2 && years[0] > years[1] {
+		return errors.New(locale.MustT("search_year_range_invalid"))
+	}
+
+	// filters typed in free text
+	for _, match := range searchFilterRegex.FindAllStringSubmatch(q.keyword, -1) {
+		field, value := strings.ToLower(match[1]), match[2]
+		switch field {
+		case "year":
+			from, to, isRange := strings.Cut(value, "-")
+			fromYear, err := parseSearchYear(from)
+			if err != nil {
+				return err
+			}
+			if isRange {
+				toYear, err := parseSearchYear(to)
+				if err != nil {
+					return err
+				}
+				if fromYear > toYear {
+					return errors.New(locale.MustT("search_year_range_invalid"))
+				}
+			}
+		case "tag":
+			if value != "new" && value != "hipster" {
+				return errors.New(locale.MustT("search_tag_invalid"))
+			}
+		}
+	}
+	return nil
+}
+
+func parseSearchYear(y string) (int, error) {
+	invalid := errors.New(locale.MustT("search_year_invalid", locale.WithTplData(map[string]string{"Year": y})))
+	if !searchYearRegex.MatchString(y) {
+		return 0, invalid
+	}
+	year, _ := strconv.Atoi(y)
+	if year < 1000 || year > time.Now().Year()+1 {
+		return 0, invalid
+	}
+	return year, nil
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSearchQueryString(t *testing.T) {
+	cases := []struct {
+		query    searchQuery
+		expected string
+	}{
+		{query: searchQuery{}, expected: ""},
+		{query: searchQuery{keyword: "  ", artist: " \t"}, expected: ""},
+		{query: searchQuery{keyword: " love "}, expected: "love"},
+		{query: searchQuery{artist: "Muse"}, expected: "artist:Muse"},
+		{query: searchQuery{keyword: "love", artist: "Daft Punk", album: " Discovery ", genre: "french house"}, expected: `love artist:"Daft Punk" album:Discovery genre:"french house"`},
+		{query: searchQuery{artist: "Daft\tPunk"}, expected: "artist:\"Daft\tPunk\""},
+		{query: searchQuery{yearFrom: "2000", yearTo: "2009"}, expected: "year:2000-2009"},
+		{query: searchQuery{yearFrom: "2000", yearTo: "2000"}, expected: "year:2000"},
+		{query: searchQuery{yearFrom: "2000"}, expected: "year:2000"},
+		{query: searchQuery{yearTo: " 2009 "}, expected: "year:2009"},
+		{query: searchQuery{keyword: "love year:1990-1999 tag:new"}, expected: "love year:1990-1999 tag:new"},
+	}
+	for _, c := range cases {
+		if s := c.query.String(); s != c.expected {
+			t.Errorf("%+v = %q, expected %q", c.query, s, c.expected)
+		}
+	}
+}
+
+func TestSearchQueryValidate(t *testing.T) {
+	nextYear := strconv.Itoa(time.Now().Year() + 1)
+	cases := []struct {
+		query searchQuery
+		valid bool
+	}{
+		{query: searchQuery{}, valid: false},
+		{query: searchQuery{keyword: " "}, valid: false},
+		{query: searchQuery{keyword: "love"}, valid: true},
+		{query: searchQuery{artist: "Daft Punk"}, valid: true},
+		{query: searchQuery{artist: `Daft "Punk`}, valid: false},
+		{query: searchQuery{album: `"Discovery"`}, valid: false},
+		{query: searchQuery{yearFrom: "2000", yearTo: "2009"}, valid: true},
+		{query: searchQuery{yearFrom: "2009", yearTo: "2000"}, valid: false},
+		{query: searchQuery{yearFrom: "200"}, valid: false},
+		{query: searchQuery{yearTo: "0999"}, valid: false},
+		{query: searchQuery{yearTo: nextYear}, valid: true},
+		{query: searchQuery{keyword: "love YEAR:1990-1999"}, valid: true},
+		{query: searchQuery{keyword: "love year:1990"}, valid: true},
+		{query: searchQuery{keyword: "love year:1999-1990"}, valid: false},
+		{query: searchQuery{keyword: "love year:19xx"}, valid: false},
+		{query: searchQuery{keyword: "love year:"}, valid: false},
+		{query: searchQuery{keyword: "tag:new"}, valid: true},
+		{query: searchQuery{keyword: "tag:hipster"}, valid: true},
+		{query: searchQuery{keyword: "tag:old"}, valid: false},
+		{query: searchQuery{keyword: "isrc:USUM71703861"}, valid: true},
+	}
+	for _, c := range cases {
+		if err := c.query.Validate(); (err == nil) != c.valid {
+			t.Errorf("%+v validate = %v, expected valid %v", c.query, err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-musicfox/spotifox/utils"
+	"github.com/zmb3/spotify/v2"
+)
+
+// songsLoaderWorkers rate limit and server errors are retried by the http transport
+const songsLoaderWorkers = 4
+
+// songsFetcher fetches a page of songs
+type songsFetcher func(ctx context.Context, offset, limit int) ([]spotify.FullTrack, error)
+
+// songsLoader loads the remaining pages of a paginated songs menu concurrently in background
+type songsLoader struct {
+	fetch   songsFetcher
+	limit   int
+	runOnUI func(func())
+
+	l       sync.Mutex
+	loading bool
+	cancel  context.CancelFunc
+	// gen is increased by Cancel, the result of canceled loading is dropped
+	gen int
+}
+
+func newSongsLoader(limit int, runOnUI func(func()), fetch songsFetcher) *songsLoader {
+	return &songsLoader{
+		fetch:   fetch,
+		limit:   limit,
+		runOnUI: runOnUI,
+	}
+}
+
+// Load fetches songs from offset to total in background, onProgress is called after each page is loaded,
+// and onComplete is called on the UI goroutine with loaded songs followed by the fetched ones,
+// or with nil if loading failed or was canceled. It returns false if the loader is already loading.
+func (l *songsLoader) Load(loaded []spotify.FullTrack, offset, total int, onProgress func(loaded, total int), onComplete func([]spotify.FullTrack)) bool {
+	l.l.Lock()
+	defer l.l.Unlock()
+	if l.loading {
+		return false
+	}
+
+	var ctx context.Context
+	ctx, l.cancel = context.WithCancel(context.Background())
+	l.loading = true
+	gen := l.gen
+
+	loaded = append([]spotify.FullTrack(nil), loaded...)
+	go utils.PanicRecoverWrapper(false, func() {
+		defer func() {
+			l.l.Lock()
+			l.loading = false
+			l.l.Unlock()
+		}()
+
+		songs, err := l.loadPages(ctx, len(loaded), offset, total, onProgress)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			utils.Logger().Printf("load all songs failed: %+v", err)
+		}
+		l.runOnUI(func() {
+			l.l.Lock()
+			canceled := l.gen != gen
+			l.l.Unlock()
+			if canceled || err != nil {
+				onComplete(nil)
+				return
+			}
+			onComplete(append(loaded, songs...))
+		})
+	})
+	return true
+}
+
+// Cancel stops loading, onComplete will be called with nil even if loading has finished but not been applied
+func (l *songsLoader) Cancel() {
+	l.l.Lock()
+	defer l.l.Unlock()
+	l.gen++
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+func (l *songsLoader) loadPages(ctx context.Context, loadedCount, offset, total int, onProgress func(loaded, total int)) ([]spotify.FullTrack, error) {
+	if offset >= total {
+		return nil, nil
+	}
+
+	var (
+		pageCount = (total - offset + l.limit - 1) / l.limit
+		pages     = make([][]spotify.FullTrack, pageCount)
+		offsets   = make(chan int)
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		firstErr  error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < songsLoaderWorkers && i < pageCount; i++ {
+		wg.Add(1)
+		go utils.PanicRecoverWrapper(false, func() {
+			defer wg.Done()
+			for pageIndex := range offsets {
+				songs, err := l.fetch(ctx, offset+pageIndex*l.limit, l.limit)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				pages[pageIndex] = songs
+				loadedCount += len(songs)
+				count := loadedCount
+				mu.Unlock()
+
+				if onProgress != nil {
+					onProgress(count, total)
+				}
+			}
+		})
+	}
+
+dispatch:
+	for i := 0; i < pageCount; i++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case offsets <- i:
+		}
+	}
+	close(offsets)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var songs []spotify.FullTrack
+	for _, page := range pages {
+		songs = append(songs, page...)
+	}
+	return songs, nil
+}
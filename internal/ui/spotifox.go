@@ -1,42 +1,79 @@
 package ui
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/anhoder/foxful-cli/model"
 	respot "github.com/arcspace/go-librespot/librespot/api-respot"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/go-musicfox/spotifox/internal/configs"
+	"github.com/go-musicfox/spotifox/internal/connect"
+	"github.com/go-musicfox/spotifox/internal/httpcache"
+	"github.com/go-musicfox/spotifox/internal/httpretry"
 	"github.com/go-musicfox/spotifox/internal/lastfm"
+	"github.com/go-musicfox/spotifox/internal/lyric"
 	"github.com/go-musicfox/spotifox/internal/player"
 	"github.com/go-musicfox/spotifox/internal/storage"
 	"github.com/go-musicfox/spotifox/internal/structs"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
 	"github.com/go-musicfox/spotifox/utils/locale"
-	lyricsapi "github.com/raitonoberu/lyricsapi/lyrics"
 	"github.com/zmb3/spotify/v2"
 	"golang.org/x/mod/semver"
 )
 
+type requestContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
 type Spotifox struct {
 	user       *structs.User
 	lastfm     *lastfm.Client
 	lastfmUser *storage.LastfmUser
 
+	savedSearches storage.SavedSearches
+	likedSongs    likedSongsIndex
+
+	// sess is replaced when disconnected, it must be used with sessL held
+	sessL         sync.Mutex
 	sess          respot.Session
+	sessGen       int
 	spotifyClient *spotify.Client
-	lyricClient   *lyricsapi.LyricsApi
+	httpCache     *httpcache.Transport
+	// cacheNamespace id of the user, read by the transport of requests
+	cacheNamespace  atomic.Value
+	httpRetry       *httpretry.Transport
+	connect         *connect.Controller
+	lyricsProviders lyric.Chain
 
 	*model.App
-	login  *LoginPage
-	search *SearchPage
+	login    *LoginPage
+	search   *SearchPage
+	openLink *OpenLinkPage
+	lyrics   *LyricsPage
+
+	// startupLink opened after initialized, passed by `spotifox open`
+	startupLink string
+	// startupProfile selected by `--profile` or the last used one, the default profile if empty
+	startupProfile string
 
 	player *Player
+
+	requestsL sync.Mutex
+	requests  map[model.Menu]requestContext
+
+	// uiTasks the results of background work, applied on the UI goroutine
+	uiTasksL sync.Mutex
+	uiTasks  []func()
 }
 
 func NewSpotifox(app *model.App) *Spotifox {
@@ -45,13 +82,28 @@ func NewSpotifox(app *model.App) *Spotifox {
 		sess:   NewSpotifySession(),
 		App:    app,
 	}
+	s.httpRetry = httpretry.NewTransport(http.DefaultTransport)
+	s.httpRetry.Timeout = types.AppHttpTimeout
+	s.httpRetry.OnRetry = func(req *http.Request, status httpretry.Status) {
+		utils.Logger().Printf("[WARN] retry %s %s, reason: %s, attempt: %d", req.Method, req.URL.Path, status.Reason, status.Attempt)
+		// requests may be made on the UI goroutine, which Rerender waits for
+		go s.Rerender(false)
+	}
+	s.httpRetry.OnDone = func(_ *http.Request) {
+		go s.Rerender(false)
+	}
+	s.httpCache = httpcache.NewTransport(s.httpRetry, httpcache.NewBoltStore())
+	s.cacheNamespace.Store("")
+	s.httpCache.Namespace = func() string {
+		return s.cacheNamespace.Load().(string)
+	}
 	s.player = NewPlayer(s)
 	s.login = NewLoginPage(s)
 	s.search = NewSearchPage(s)
+	s.openLink = NewOpenLinkPage(s)
+	s.lyrics = NewLyricsPage(s)
 
-	if configs.ConfigRegistry.Main.ShowLyric && configs.ConfigRegistry.Spotify.Cookie != "" {
-		s.lyricClient = lyricsapi.NewLyricsApi(configs.ConfigRegistry.Spotify.Cookie)
-	}
+	s.lyricsProviders = newLyricsProviders()
 
 	return s
 }
@@ -74,11 +126,9 @@ func (s *Spotifox) HandleResCode(code utils.ResCode, callback LoginCallback) (bo
 func (s *Spotifox) ToLoginPage(callback LoginCallback) (model.Page, tea.Cmd) {
 	s.login.AfterLogin = callback
 	if s.user != nil && s.user.Username != "" && len(s.user.AuthBlob) > 0 {
-		login := &s.sess.Context().Login
-		login.Username = s.user.Username
-		login.AuthData = s.user.AuthBlob
-		err := s.ReconnSessionWhenNeed(func() error {
-			return s.sess.Login()
+		creds := s.credentialsOf(s.user)
+		err := s.ReconnSessionWhenNeed(func(sess respot.Session) error {
+			return loginByAuthBlob(sess, creds)
 		})
 		if err == nil {
 			return s.login.handleLoginSuccess()
@@ -89,11 +139,38 @@ func (s *Spotifox) ToLoginPage(callback LoginCallback) (model.Page, tea.Cmd) {
 	return s.login, tickLogin(time.Nanosecond)
 }
 
-func (s *Spotifox) ToSearchPage(searchType spotify.SearchType) (model.Page, tea.Cmd) {
-	s.search.searchType = searchType
+func (s *Spotifox) ToSearchPage() (model.Page, tea.Cmd) {
+	if s.search.history == nil {
+		s.search.loadHistory()
+	}
 	return s.search, tickSearch(time.Nanosecond)
 }
 
+func (s *Spotifox) ToOpenLinkPage() (model.Page, tea.Cmd) {
+	return s.openLink, tickOpenLink(time.Nanosecond)
+}
+
+func (s *Spotifox) ToLyricsPage() (model.Page, tea.Cmd) {
+	return s.lyrics, tickLyrics(time.Nanosecond)
+}
+
+// SetStartupLink sets the link opened after initialized
+func (s *Spotifox) SetStartupLink(link string) {
+	s.startupLink = link
+}
+
+// openStartupLink opens the link passed by command line once
+func (s *Spotifox) openStartupLink() model.Page {
+	link := s.startupLink
+	s.startupLink = ""
+	return openSpotifyLink(s, link)
+}
+
+// SetStartupProfile sets the profile used after initialized
+func (s *Spotifox) SetStartupProfile(name string) {
+	s.startupProfile = name
+}
+
 func (s *Spotifox) InitHook(_ *model.App) {
 	config := configs.ConfigRegistry
 	// projectDir := utils.GetLocalDataDir()
@@ -102,64 +179,28 @@ func (s *Spotifox) InitHook(_ *model.App) {
 	// cookieJar, _ := cookiejar.NewFileJar(path.Join(projectDir, "cookie"), nil)
 	// util.SetGlobalCookieJar(cookieJar)
 
-	// DBManager init
-	storage.DBManager = new(storage.LocalDBManager)
+	// DBManager init, it may be opened when loading the secret key
+	if storage.DBManager == nil {
+		storage.DBManager = new(storage.LocalDBManager)
+	}
 
 	go utils.PanicRecoverWrapper(false, func() {
-		table := storage.NewTable()
-
-		// get user info
-		if jsonStr, err := table.GetByKVModel(storage.User{}); err == nil {
-			if user, err := structs.NewUserFromLocalJson(jsonStr); err == nil {
-				s.user = &user
-			}
+		// select profile before reading the data of account
+		name := s.startupProfile
+		if name == "" {
+			name = storage.DefaultProfile
 		}
-		// refresh username
-		s.MustMain().RefreshMenuTitle()
-
-		// get user info of lastfm
-		var lastfmUser storage.LastfmUser
-		if jsonStr, err := table.GetByKVModel(&lastfmUser); err == nil {
-			if err = json.Unmarshal(jsonStr, &lastfmUser); err == nil {
-				s.lastfmUser = &lastfmUser
-				s.lastfm.SetSession(lastfmUser.SessionKey)
-			}
-		}
-		s.MustMain().RefreshMenuList()
+		storage.SetCurProfile(name)
 
-		// get play mode
-		if jsonStr, err := table.GetByKVModel(storage.PlayMode{}); err == nil && len(jsonStr) > 0 {
-			var playMode player.Mode
-			if err = json.Unmarshal(jsonStr, &playMode); err == nil {
-				s.player.mode = playMode
-			}
-		}
+		s.loadProfile()
+		s.Rerender(false)
 
-		// get player volume
-		if jsonStr, err := table.GetByKVModel(storage.Volume{}); err == nil && len(jsonStr) > 0 {
-			var volume int
-			if err = json.Unmarshal(jsonStr, &volume); err == nil {
-				v, ok := s.player.Player.(storage.VolumeStorable)
-				if ok {
-					v.SetVolume(volume)
-				}
-			}
-		}
+		// submit the scrobbles in background, including the ones left by last run
+		go utils.PanicRecoverWrapper(false, func() {
+			s.lastfm.ScrobbleQueue().Run(context.Background())
+		})
 
-		// get playing info
-		if jsonStr, err := table.GetByKVModel(storage.PlayerSnapshot{}); err == nil && len(jsonStr) > 0 {
-			var snapshot storage.PlayerSnapshot
-			if err = json.Unmarshal(jsonStr, &snapshot); err == nil {
-				p := s.player
-				p.curSongIndex = snapshot.CurSongIndex
-				p.playlist = snapshot.Playlist
-				p.playlistUpdateAt = snapshot.PlaylistUpdateAt
-				p.curSong = p.playlist[p.curSongIndex]
-				p.isCurSongLiked = snapshot.IsCurSongLiked
-				p.playingMenuKey = "from_local_db" // reset menu key
-			}
-		}
-		s.Rerender(false)
+		table := storage.NewTable()
 
 		// get ext info
 		{
@@ -207,9 +248,156 @@ func (s *Spotifox) InitHook(_ *model.App) {
 				})
 			}
 		}
+
+		// open the link passed by command line, it's opened after login if not logged in.
+		// Menus and player are changed by opening, so it runs on the UI goroutine
+		s.runOnUI(func() {
+			if s.startupLink == "" {
+				return
+			}
+			if s.CheckAuthSession() != utils.NeedLogin {
+				s.openStartupLink()
+			} else if page, _ := s.ToLoginPage(nil); page == model.Page(s.login) {
+				// login page can't be shown here, the link is kept until logged in
+				model.NewMenuTips(s.MustMain(), nil).DisplayTips(locale.MustT("login_before_open_link"))
+			}
+		})
 	})
 }
 
+// loadProfile reads the account, Last.fm session, searches and play state of current profile
+func (s *Spotifox) loadProfile() {
+	table := storage.NewTable()
+
+	// get user info
+	if jsonStr, err := table.GetByKVModel(storage.User{}); err == nil {
+		if user, err := structs.NewUserFromLocalJson(jsonStr); err == nil {
+			s.user = &user
+			s.likedSongs.Load(user.ID)
+		}
+	}
+	s.updateCacheNamespace()
+	// refresh username
+	s.MustMain().RefreshMenuTitle()
+
+	// get user info of lastfm
+	var lastfmUser storage.LastfmUser
+	if jsonStr, err := table.GetByKVModel(&lastfmUser); err == nil {
+		if err = json.Unmarshal(jsonStr, &lastfmUser); err == nil {
+			s.lastfmUser = &lastfmUser
+			s.lastfm.SetSession(string(lastfmUser.SessionKey))
+		}
+	}
+
+	// the secrets stored as plaintext before are sealed once
+	if sealed, _ := table.GetByKVModel(storage.SecretsSealed{}); len(sealed) == 0 {
+		var err error
+		if s.user != nil {
+			err = table.SetByKVModel(storage.User{}, s.user)
+		}
+		if err == nil && s.lastfmUser != nil {
+			err = table.SetByKVModel(s.lastfmUser, s.lastfmUser)
+		}
+		if err == nil {
+			_ = table.SetByKVModel(storage.SecretsSealed{}, true)
+		} else {
+			utils.Logger().Printf("seal secrets failed: %+v", err)
+		}
+	}
+
+	// get saved searches
+	if jsonStr, err := table.GetByKVModel(storage.SavedSearches{}); err == nil && len(jsonStr) > 0 {
+		_ = json.Unmarshal(jsonStr, &s.savedSearches)
+	}
+	s.MustMain().RefreshMenuList()
+
+	// get play mode
+	if jsonStr, err := table.GetByKVModel(storage.PlayMode{}); err == nil && len(jsonStr) > 0 {
+		var playMode player.Mode
+		if err = json.Unmarshal(jsonStr, &playMode); err == nil {
+			s.player.mode = playMode
+		}
+	}
+
+	// get player volume
+	if jsonStr, err := table.GetByKVModel(storage.Volume{}); err == nil && len(jsonStr) > 0 {
+		var volume int
+		if err = json.Unmarshal(jsonStr, &volume); err == nil {
+			v, ok := s.player.Player.(storage.VolumeStorable)
+			if ok {
+				v.SetVolume(volume)
+			}
+		}
+	}
+
+	// get playing info
+	if jsonStr, err := table.GetByKVModel(storage.PlayerSnapshot{}); err == nil && len(jsonStr) > 0 {
+		var snapshot storage.PlayerSnapshot
+		if err = json.Unmarshal(jsonStr, &snapshot); err == nil {
+			p := s.player
+			p.curSongIndex = snapshot.CurSongIndex
+			p.playlist = snapshot.Playlist
+			p.playlistUpdateAt = snapshot.PlaylistUpdateAt
+			p.curSong = p.playlist[p.curSongIndex]
+			p.isCurSongLiked = snapshot.IsCurSongLiked
+			p.playingMenuKey = "from_local_db" // reset menu key
+		}
+	}
+}
+
+// SwitchProfile tears down the session, Web API client and player of current profile,
+// then rebuilds them with the data of the profile, which is added if not exists.
+// done is called on the UI goroutine after switched
+func (s *Spotifox) SwitchProfile(name string, done func(err error)) {
+	profiles := storage.LoadProfiles()
+	if err := profiles.Use(name); err != nil {
+		done(err)
+		return
+	}
+	if name == storage.CurProfile() {
+		done(nil)
+		return
+	}
+
+	s.requestsL.Lock()
+	for menu, r := range s.requests {
+		r.cancel()
+		delete(s.requests, menu)
+	}
+	s.requestsL.Unlock()
+
+	// the loops of player may be waiting for the UI goroutine, so they're waited in background
+	go utils.PanicRecoverWrapper(false, func() {
+		s.player.StopLoops()
+		s.runOnUI(func() {
+			s.resetProfile(name)
+			done(nil)
+		})
+	})
+}
+
+// resetProfile clears the state of current profile and loads the state and settings of profile name.
+// The settings applied at startup, such as language and layout, are changed after restarted with the profile
+func (s *Spotifox) resetProfile(name string) {
+	// the player is rebuilt by the settings of profile
+	utils.LoadProfileIniConfig(name)
+	s.player.Reset()
+	s.sessL.Lock()
+	s.replaceSession()
+	s.sessGen++
+	s.sessL.Unlock()
+	s.spotifyClient, s.connect = nil, nil
+	s.user, s.lastfmUser = nil, nil
+	s.updateCacheNamespace()
+	s.lastfm.SetSession("")
+	s.savedSearches = nil
+	s.likedSongs.Load("")
+	s.search.history = nil
+
+	storage.SetCurProfile(name)
+	s.loadProfile()
+}
+
 func (s *Spotifox) CloseHook(_ *model.App) {
 	s.player.Close()
 }
@@ -217,3 +405,101 @@ func (s *Spotifox) CloseHook(_ *model.App) {
 func (s *Spotifox) Player() *Player {
 	return s.player
 }
+
+// updateCacheNamespace separates the cached responses of the user, it must be called after user is changed
+func (s *Spotifox) updateCacheNamespace() {
+	var namespace string
+	if s.user != nil {
+		namespace = s.user.ID
+	}
+	s.cacheNamespace.Store(namespace)
+}
+
+// runOnUI queues f to run on the UI goroutine before the next render,
+// so that the state read by UI isn't modified by background goroutines
+func (s *Spotifox) runOnUI(f func()) {
+	s.uiTasksL.Lock()
+	s.uiTasks = append(s.uiTasks, f)
+	s.uiTasksL.Unlock()
+	// it may be called on the UI goroutine, e.g. by the transport of requests made in hooks
+	go s.Rerender(false)
+}
+
+// applyUITasks runs the queued tasks, it must be called on the UI goroutine, e.g. in View
+func (s *Spotifox) applyUITasks() {
+	s.uiTasksL.Lock()
+	tasks := s.uiTasks
+	s.uiTasks = nil
+	s.uiTasksL.Unlock()
+	if len(tasks) == 0 {
+		return
+	}
+	for _, f := range tasks {
+		f()
+	}
+	// the menu has been drawn in this frame, Rerender can't be called in the event loop
+	go s.Rerender(false)
+}
+
+// requestContext context of the requests made by the menu, canceled when leaving the menu
+func (s *Spotifox) requestContext(menu model.Menu) context.Context {
+	s.requestsL.Lock()
+	defer s.requestsL.Unlock()
+	if r, ok := s.requests[menu]; ok {
+		return r.ctx
+	}
+	if s.requests == nil {
+		s.requests = make(map[model.Menu]requestContext)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.requests[menu] = requestContext{ctx: ctx, cancel: cancel}
+	return ctx
+}
+
+// cancelRequests cancels the requests of the menu, e.g. waiting for rate limit
+func (s *Spotifox) cancelRequests(menu model.Menu) {
+	s.requestsL.Lock()
+	defer s.requestsL.Unlock()
+	if r, ok := s.requests[menu]; ok {
+		r.cancel()
+		delete(s.requests, menu)
+	}
+}
+
+// syncLikedSongs syncs the local index of liked songs in background
+func (s *Spotifox) syncLikedSongs() {
+	go utils.PanicRecoverWrapper(false, func() {
+		if err := s.likedSongs.Sync(context.Background(), s.spotifyClient); err != nil {
+			utils.Logger().Printf("%+v", err)
+			return
+		}
+		s.Rerender(false)
+	})
+}
+
+// SaveSearch saves the query by name, the query with same name will be replaced
+func (s *Spotifox) SaveSearch(name, query string) {
+	saved := storage.SavedSearches{}
+	for _, v := range s.savedSearches {
+		if v.Name != name {
+			saved = append(saved, v)
+		}
+	}
+	s.savedSearches = append(saved, storage.SavedSearch{Name: name, Query: query})
+
+	table := storage.NewTable()
+	_ = table.SetByKVModel(storage.SavedSearches{}, s.savedSearches)
+}
+
+func (s *Spotifox) RemoveSavedSearch(name string) {
+	saved := storage.SavedSearches{}
+	for _, v := range s.savedSearches {
+		if v.Name != name {
+			saved = append(saved, v)
+		}
+	}
+	s.savedSearches = saved
+
+	table := storage.NewTable()
+	_ = table.SetByKVModel(storage.SavedSearches{}, s.savedSearches)
+}
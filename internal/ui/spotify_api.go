@@ -2,18 +2,27 @@ package ui
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/arcspace/go-arc-sdk/stdlib/task"
 	respot "github.com/arcspace/go-librespot/librespot/api-respot"
 	"github.com/arcspace/go-librespot/librespot/core"
+	"github.com/arcspace/go-librespot/librespot/mercury"
 	"github.com/go-musicfox/spotifox/internal/configs"
-	"github.com/go-musicfox/spotifox/internal/lyric"
+	"github.com/go-musicfox/spotifox/internal/storage"
+	"github.com/go-musicfox/spotifox/internal/structs"
 	"github.com/go-musicfox/spotifox/internal/types"
 	"github.com/go-musicfox/spotifox/utils"
+	"github.com/go-musicfox/spotifox/utils/auth"
+	"github.com/go-musicfox/spotifox/utils/locale"
+	"github.com/go-musicfox/spotifox/utils/secret"
+	"github.com/skratchdot/open-golang/open"
 	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2"
 )
 
 func NewSpotifySession() respot.Session {
@@ -29,20 +38,168 @@ func NewSpotifySession() respot.Session {
 	return sess
 }
 
-func (s *Spotifox) ReconnSessionWhenNeed(f func() error) error {
+// ReconnSessionWhenNeed calls f with the session, which is replaced by a new one if it's disconnected, and f is retried.
+// The session is locked while f runs, so f must use the session passed rather than s.sess
+func (s *Spotifox) ReconnSessionWhenNeed(f func(sess respot.Session) error) error {
+	s.sessL.Lock()
+	defer s.sessL.Unlock()
 	var err error
 	for i := 0; i < 3; i++ {
-		err = f()
+		err = f(s.sess)
 		if err == nil {
 			return nil
 		}
 		if s.CheckConnectErr(err) == utils.NeedReconnect {
-			s.sess = NewSpotifySession()
+			s.replaceSession()
 		}
 	}
 	return err
 }
 
+// withSession calls f with the session locked, f must use the session passed rather than s.sess
+func (s *Spotifox) withSession(f func(sess respot.Session) error) error {
+	s.sessL.Lock()
+	defer s.sessL.Unlock()
+	return f(s.sess)
+}
+
+// replaceSession closes the session and starts a new one, sessL must be held
+func (s *Spotifox) replaceSession() {
+	if s.sess != nil {
+		_ = s.sess.Close()
+	}
+	s.sess = NewSpotifySession()
+}
+
+// sessionCredentials the credentials to restore the session, they're copied from user on the UI goroutine,
+// so that refreshing token in the transport of requests doesn't read user
+type sessionCredentials struct {
+	username     string
+	authBlob     []byte
+	refreshToken string
+	// gen the generation of session when copied, the session of another profile isn't logged in by them
+	gen int
+}
+
+func (s *Spotifox) credentialsOf(user *structs.User) *sessionCredentials {
+	s.sessL.Lock()
+	defer s.sessL.Unlock()
+	creds := &sessionCredentials{gen: s.sessGen}
+	if user != nil {
+		creds.username = user.Username
+		creds.authBlob = append([]byte(nil), user.AuthBlob...)
+		creds.refreshToken = string(user.RefreshToken)
+	}
+	return creds
+}
+
+// loginByAuthBlob logs in the session by the stored credentials
+func loginByAuthBlob(sess respot.Session, creds *sessionCredentials) error {
+	if creds.username == "" || len(creds.authBlob) == 0 {
+		return errors.New("auth blob is empty")
+	}
+	login := &sess.Context().Login
+	login.Username = creds.username
+	login.AuthData = creds.authBlob
+	return sess.Login()
+}
+
+// tokenRefresher gets new access tokens of Web API for the credentials, the session is reconnected if needed.
+// It's called by the token source, which serializes the calls
+func (s *Spotifox) tokenRefresher(creds *sessionCredentials) auth.TokenFetcher {
+	return func() (*oauth2.Token, error) {
+		return s.refreshAccessToken(creds)
+	}
+}
+
+func (s *Spotifox) refreshAccessToken(creds *sessionCredentials) (*oauth2.Token, error) {
+	var (
+		token    *mercury.Token
+		getToken = func(sess respot.Session) (err error) {
+			if s.sessGen != creds.gen {
+				return errors.New("profile of session is switched")
+			}
+			token, err = sess.Mercury().GetToken(configs.ConfigRegistry.Spotify.ClientId, types.SpotifyOAuthScopes)
+			return
+		}
+	)
+	err := s.withSession(getToken)
+	if err != nil && s.CheckConnectErr(err) == utils.NeedReconnect {
+		err = s.ReconnSessionWhenNeed(func(sess respot.Session) error {
+			if s.sessGen != creds.gen {
+				return errors.New("profile of session is switched")
+			}
+			if err := loginByAuthBlob(sess, creds); err != nil {
+				return err
+			}
+			return getToken(sess)
+		})
+	}
+	if err != nil && creds.refreshToken != "" {
+		// the refresh token of browser login still works when session can't be restored
+		return s.refreshByRefreshToken(creds)
+	}
+	if err != nil {
+		utils.Logger().Printf("refresh access token failed: %+v", err)
+		return nil, err
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, errors.New("get access token failed")
+	}
+	// it's called by the transport of Web API client, the user is read by UI
+	s.runOnUI(func() {
+		if s.user != nil {
+			s.user.Token = *token
+		}
+	})
+	return oauth2TokenOf(token), nil
+}
+
+func (s *Spotifox) refreshByRefreshToken(creds *sessionCredentials) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), types.AppHttpTimeout)
+	defer cancel()
+	token, err := s.oauthConfig().Refresh(ctx, creds.refreshToken)
+	if err != nil {
+		utils.Logger().Printf("refresh access token by refresh token failed: %+v", err)
+		return nil, err
+	}
+	// refresh token may be rotated
+	if refreshToken := token.RefreshToken; refreshToken != "" && refreshToken != creds.refreshToken {
+		creds.refreshToken = refreshToken
+		s.runOnUI(func() {
+			if s.user == nil {
+				return
+			}
+			s.user.RefreshToken = secret.String(refreshToken)
+			table := storage.NewTable()
+			_ = table.SetByKVModel(storage.User{}, s.user)
+		})
+	}
+	return token, nil
+}
+
+// oauthConfig authorization code flow with PKCE, used by browser login
+func (s *Spotifox) oauthConfig() auth.PKCEConfig {
+	return auth.PKCEConfig{
+		ClientId:    configs.ConfigRegistry.Spotify.ClientId,
+		AuthURL:     types.SpotifyAuthURL,
+		TokenURL:    types.SpotifyTokenURL,
+		Scopes:      strings.Split(types.SpotifyWebAuthScopes, ","),
+		Port:        configs.ConfigRegistry.Spotify.OAuthPort,
+		OpenURL:     open.Start,
+		SuccessHTML: locale.MustT("browser_login_success_html"),
+		FailureHTML: locale.MustT("browser_login_failed_html"),
+	}
+}
+
+func oauth2TokenOf(token *mercury.Token) *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}
+}
+
 func (s *Spotifox) CheckAuthSession() utils.ResCode {
 	if s.spotifyClient == nil {
 		return utils.NeedLogin
@@ -55,32 +212,42 @@ func (s *Spotifox) CheckAuthSession() utils.ResCode {
 }
 
 func (s *Spotifox) CheckConnectErr(err error) utils.ResCode {
-	if s.sess == nil || errors.Is(err, io.EOF) {
+	if errors.Is(err, io.EOF) {
 		return utils.NeedReconnect
 	}
 	return utils.UnknownError
 }
 
-func (s *Spotifox) FetchSongLyrics(songId spotify.ID) *lyric.LRCFile {
-	if s.lyricClient == nil {
-		lrcFile, _ := lyric.ReadLRC(strings.NewReader("[00:00.00] No Lyrics~ (Please set your cookies in the configuration file)"))
-		return lrcFile
+func (s *Spotifox) FetchAudioFeatures(songId spotify.ID) (*spotify.AudioFeatures, error) {
+	var (
+		table    = storage.NewTable()
+		key      = storage.AudioFeatures{TrackId: songId}
+		features spotify.AudioFeatures
+	)
+	if jsonStr, err := table.GetByKVModel(key); err == nil && len(jsonStr) > 0 {
+		if err = json.Unmarshal(jsonStr, &features); err == nil {
+			return &features, nil
+		}
 	}
 
-	// get by user's cookie
-	l, err := s.lyricClient.Get(string(songId))
-	if err != nil || l == nil || l.Lyrics == nil {
-		utils.Logger().Printf("get song lyrics failed: %+v", err)
-		return nil
+	if s.spotifyClient == nil {
+		return nil, errors.New("spotify client is nil")
+	}
+	res, err := s.spotifyClient.GetAudioFeatures(context.Background(), songId)
+	if err != nil {
+		return nil, err
 	}
-	var frags []lyric.LRCFragment
-	for _, v := range l.Lyrics.Lines {
-		frags = append(frags, lyric.LRCFragment{StartTimeMs: int64(v.Time), Content: v.Words})
+	if len(res) == 0 || res[0] == nil {
+		return nil, errors.New("audio features not found")
 	}
-	return lyric.NewLRCFileFromFrags(frags)
+	_ = table.SetByKVModel(key, res[0])
+	return res[0], nil
 }
 
 func (s *Spotifox) CheckLikedSong(songId spotify.ID) bool {
+	if liked, known := s.likedSongs.Liked(songId); known {
+		return liked
+	}
 	if s.spotifyClient == nil {
 		return false
 	}
@@ -109,6 +276,41 @@ func (s *Spotifox) LikeSong(songId spotify.ID, likeOrNot bool) bool {
 		utils.Logger().Printf("Change liked song failed: %+v", err)
 		return false
 	}
+	s.likedSongs.Set(songId, likeOrNot)
+	return true
+}
+
+func (s *Spotifox) SaveAlbum(albumId spotify.ID, saveOrNot bool) bool {
+	if s.spotifyClient == nil {
+		return false
+	}
+	var err error
+	if saveOrNot {
+		err = s.spotifyClient.AddAlbumsToLibrary(context.Background(), albumId)
+	} else {
+		err = s.spotifyClient.RemoveAlbumsFromLibrary(context.Background(), albumId)
+	}
+	if err != nil {
+		utils.Logger().Printf("Change saved album failed: %+v", err)
+		return false
+	}
+	return true
+}
+
+func (s *Spotifox) FollowArtist(id spotify.ID, followOrNot bool) bool {
+	if s.spotifyClient == nil {
+		return false
+	}
+	var err error
+	if followOrNot {
+		err = s.spotifyClient.FollowArtist(context.Background(), id)
+	} else {
+		err = s.spotifyClient.UnfollowArtist(context.Background(), id)
+	}
+	if err != nil {
+		utils.Logger().Printf("Change followed artist failed: %+v", err)
+		return false
+	}
 	return true
 }
 
@@ -128,3 +330,12 @@ func (s *Spotifox) FollowPlaylist(id spotify.ID, followOrNot bool) bool {
 	}
 	return true
 }
+
+// searchOptions options of search request, results are limited to the market of user
+func (s *Spotifox) searchOptions(offset int) []spotify.RequestOption {
+	opts := []spotify.RequestOption{spotify.Limit(types.SearchPageSize), spotify.Offset(offset)}
+	if s.user != nil && s.user.Country != "" {
+		opts = append(opts, spotify.Market(s.user.Country))
+	}
+	return opts
+}
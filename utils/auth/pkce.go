@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const callbackPath = "/callback"
+
+// PKCEConfig authorization code flow with PKCE, the code is received by a temporary
+// callback server listening on localhost
+type PKCEConfig struct {
+	ClientId string
+	AuthURL  string
+	TokenURL string
+	Scopes   []string
+	// Port of callback server, must match the redirect uri registered, zero means random port
+	Port int
+	// OpenURL opens the authorize url, e.g. in browser
+	OpenURL func(url string) error
+	// SuccessHTML, FailureHTML are shown in browser after callback
+	SuccessHTML string
+	FailureHTML string
+}
+
+func (c PKCEConfig) oauth2Config(redirectURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:    c.ClientId,
+		RedirectURL: redirectURL,
+		Scopes:      c.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   c.AuthURL,
+			TokenURL:  c.TokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Authorize opens the authorize url and waits for the callback until ctx is done,
+// then exchanges the code for tokens
+func (c PKCEConfig) Authorize(ctx context.Context) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(c.Port)))
+	if err != nil {
+		return nil, errors.Wrap(err, "listen callback failed")
+	}
+	redirectURL := fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+
+	verifier, err := randomString(32)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	var (
+		results = make(chan callbackResult, 1)
+		mux     = http.NewServeMux()
+		server  = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	)
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var res callbackResult
+		switch {
+		case query.Get("state") != state:
+			res.err = errors.New("state mismatched")
+		case query.Get("error") != "":
+			res.err = errors.Errorf("authorization failed: %s", query.Get("error"))
+		case query.Get("code") == "":
+			res.err = errors.New("code is empty")
+		default:
+			res.code = query.Get("code")
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if res.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(c.FailureHTML))
+		} else {
+			_, _ = w.Write([]byte(c.SuccessHTML))
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	conf := c.oauth2Config(redirectURL)
+	authURL := conf.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", challengeOf(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	if c.OpenURL != nil {
+		if err = c.OpenURL(authURL); err != nil {
+			return nil, errors.Wrap(err, "open authorize url failed")
+		}
+	}
+
+	var res callbackResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	token, err := conf.Exchange(ctx, res.code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "exchange token failed")
+	}
+	return token, nil
+}
+
+// Refresh gets a new token by the refresh token, the refresh token may be rotated
+func (c PKCEConfig) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	token, err := c.oauth2Config("").TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "refresh token failed")
+	}
+	return token, nil
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "generate random string failed")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func challengeOf(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer authorizes every request, and checks the code verifier when exchanging
+func fakeAuthServer(t *testing.T) *httptest.Server {
+	challenges := map[string]string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/authorize":
+			query := r.URL.Query()
+			if query.Get("code_challenge_method") != "S256" || query.Get("response_type") != "code" {
+				t.Errorf("unexpected authorize query: %s", r.URL.RawQuery)
+			}
+			challenges["fake-code"] = query.Get("code_challenge")
+			redirect, _ := url.Parse(query.Get("redirect_uri"))
+			redirect.RawQuery = url.Values{"code": {"fake-code"}, "state": {query.Get("state")}}.Encode()
+			http.Redirect(w, r, redirect.String(), http.StatusFound)
+		case "/api/token":
+			_ = r.ParseForm()
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Form.Get("grant_type") {
+			case "authorization_code":
+				if challengeOf(r.Form.Get("code_verifier")) != challenges[r.Form.Get("code")] {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access-1", "refresh_token": "refresh-1", "token_type": "Bearer", "expires_in": 3600})
+			case "refresh_token":
+				if r.Form.Get("refresh_token") != "refresh-1" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access-2", "refresh_token": "refresh-2", "token_type": "Bearer", "expires_in": 3600})
+			}
+		}
+	}))
+}
+
+func TestAuthorizeByPKCE(t *testing.T) {
+	srv := fakeAuthServer(t)
+	defer srv.Close()
+
+	conf := PKCEConfig{
+		ClientId: "client",
+		AuthURL:  srv.URL + "/authorize",
+		TokenURL: srv.URL + "/api/token",
+		Scopes:   []string{"streaming"},
+		OpenURL: func(u string) error {
+			// the browser follows the redirect to callback server
+			go func() {
+				res, err := http.Get(u)
+				if err != nil {
+					t.Errorf("open authorize url: %v", err)
+					return
+				}
+				_ = res.Body.Close()
+			}()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	token, err := conf.Authorize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("token = %+v", token)
+	}
+
+	token, err = conf.Refresh(ctx, token.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-2" || token.RefreshToken != "refresh-2" {
+		t.Errorf("refreshed token = %+v", token)
+	}
+}
+
+func TestAuthorizeStateMismatched(t *testing.T) {
+	conf := PKCEConfig{
+		ClientId: "client",
+		AuthURL:  "http://127.0.0.1:1/authorize",
+		TokenURL: "http://127.0.0.1:1/api/token",
+		OpenURL: func(u string) error {
+			authURL, _ := url.Parse(u)
+			callback := authURL.Query().Get("redirect_uri") + "?code=x&state=forged"
+			go func() {
+				res, err := http.Get(callback)
+				if err == nil {
+					_ = res.Body.Close()
+				}
+			}()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := conf.Authorize(ctx); err == nil {
+		t.Error("forged state should be rejected")
+	}
+}
+
+func TestAuthorizeCanceled(t *testing.T) {
+	conf := PKCEConfig{ClientId: "client", AuthURL: "http://127.0.0.1:1/authorize", TokenURL: "http://127.0.0.1:1/api/token"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := conf.Authorize(ctx); err == nil {
+		t.Error("authorize should stop when ctx is done")
+	}
+}
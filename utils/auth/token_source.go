@@ -2,21 +2,61 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
 )
 
-var _ oauth2.TokenSource = (*TokenSourceWrapper)(nil)
-
-type TokenSourceWrapper oauth2.Token
+var _ oauth2.TokenSource = (*RefreshTokenSource)(nil)
 
 var ErrTokenExpired = errors.New("Token has expired")
 
-func (s *TokenSourceWrapper) Token() (*oauth2.Token, error) {
-	if time.Now().After(s.Expiry) {
-		return nil, ErrTokenExpired
+// RefreshAhead the token is refreshed before it expires in the duration
+const RefreshAhead = time.Minute
+
+// TokenFetcher fetches a new token, whose Expiry is the real expiry time
+type TokenFetcher func() (*oauth2.Token, error)
+
+// RefreshTokenSource refreshes the token ahead of expiry, concurrent callers
+// share one refreshing
+type RefreshTokenSource struct {
+	l     sync.Mutex
+	token *oauth2.Token
+	fetch TokenFetcher
+}
+
+func NewRefreshTokenSource(token *oauth2.Token, fetch TokenFetcher) *RefreshTokenSource {
+	return &RefreshTokenSource{
+		token: token,
+		fetch: fetch,
+	}
+}
+
+func (s *RefreshTokenSource) Token() (*oauth2.Token, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.token != nil && time.Now().Add(RefreshAhead).Before(s.token.Expiry) {
+		return s.copyToken(), nil
 	}
 
-	return (*oauth2.Token)(s), nil
+	token, err := s.fetch()
+	if err != nil || token == nil || token.AccessToken == "" {
+		// the old one can be used until it really expires
+		if s.token != nil && time.Now().Before(s.token.Expiry) {
+			return s.copyToken(), nil
+		}
+		return nil, fmt.Errorf("%w: refresh failed: %v", ErrTokenExpired, err)
+	}
+	s.token = token
+	return s.copyToken(), nil
+}
+
+// copyToken returns the token with shortened expiry, so that oauth2.ReuseTokenSource asks for refreshing in time
+func (s *RefreshTokenSource) copyToken() *oauth2.Token {
+	token := *s.token
+	token.Expiry = token.Expiry.Add(-RefreshAhead)
+	return &token
 }
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestRefreshAheadOfExpiry(t *testing.T) {
+	var fetches atomic.Int32
+	source := NewRefreshTokenSource(&oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(30 * time.Second)}, func() (*oauth2.Token, error) {
+		fetches.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &oauth2.Token{AccessToken: "new", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token()
+			if err != nil || token.AccessToken != "new" {
+				t.Errorf("token = %+v, err = %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetches.Load() != 1 {
+		t.Errorf("concurrent callers should share one refreshing, fetches = %d", fetches.Load())
+	}
+}
+
+func TestRefreshFailed(t *testing.T) {
+	fetchErr := errors.New("network is down")
+	source := NewRefreshTokenSource(&oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(30 * time.Second)}, func() (*oauth2.Token, error) {
+		return nil, fetchErr
+	})
+
+	// not expired yet
+	if token, err := source.Token(); err != nil || token.AccessToken != "old" {
+		t.Errorf("token = %+v, err = %v", token, err)
+	}
+
+	source.token.Expiry = time.Now().Add(-time.Second)
+	if _, err := source.Token(); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("err = %v, expected ErrTokenExpired", err)
+	}
+}
@@ -46,3 +46,15 @@ func MenuItemsFromPlaylists(playlists []spotify.SimplePlaylist) []model.MenuItem
 	}
 	return menus
 }
+
+func MenuItemsFromShows(shows []spotify.FullShow) []model.MenuItem {
+	var menus []model.MenuItem
+	for _, show := range shows {
+		var publisher string
+		if show.Publisher != "" {
+			publisher = "[" + show.Publisher + "]"
+		}
+		menus = append(menus, model.MenuItem{Title: ReplaceSpecialStr(show.Name), Subtitle: ReplaceSpecialStr(publisher)})
+	}
+	return menus
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"net"
 	"net/http"
 	"strings"
 
@@ -17,18 +19,37 @@ const (
 	NeedLogin
 	NeedReconnect
 	TokenExpired
+	Canceled
+	RateLimited
+	ServerError
+	NetworkError
 )
 
 func CheckSpotifyErr(err error) ResCode {
 	if err == nil {
 		return Success
 	}
-	if e, ok := err.(spotify.Error); ok && e.Status == http.StatusUnauthorized {
-		return NeedLogin
+	var e spotify.Error
+	if errors.As(err, &e) {
+		switch {
+		case e.Status == http.StatusUnauthorized:
+			return NeedLogin
+		case e.Status == http.StatusTooManyRequests:
+			return RateLimited
+		case e.Status >= http.StatusInternalServerError:
+			return ServerError
+		}
 	}
 	if errors.Is(err, auth.ErrTokenExpired) {
 		return TokenExpired
 	}
+	if errors.Is(err, context.Canceled) {
+		return Canceled
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return NetworkError
+	}
 	return UnknownError
 }
 
@@ -0,0 +1,235 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+type Store string
+
+const (
+	// StoreAuto prefers keyring, then passphrase if given, keyfile at last
+	StoreAuto       Store = "auto"
+	StoreKeyring    Store = "keyring"
+	StorePassphrase Store = "passphrase"
+	StoreKeyfile    Store = "keyfile"
+)
+
+var (
+	ErrKeyNotFound        = errors.New("secret key not found")
+	ErrKeyringUnavailable = errors.New("keyring is not available")
+)
+
+// passphraseCheck is sealed by the key derived from passphrase, for telling a wrong passphrase
+const passphraseCheck = "spotifox"
+
+type KeyOptions struct {
+	Store Store
+	// Service and Account identify the key in keyring
+	Service string
+	Account string
+	// KeyFile stores the random key in keyfile mode
+	KeyFile string
+	// PassphraseFile stores the salt and the check value of passphrase
+	PassphraseFile string
+	// Passphrase asks for the passphrase, nil means no passphrase in auto mode
+	Passphrase func() (string, error)
+	// Recorded the store whose key sealed the stored secrets, empty if nothing is sealed yet.
+	// The key must be loaded from it, a new key is never generated, or the secrets can't be opened any more
+	Recorded Store
+}
+
+// LoadKey returns the key in the store, a new key is generated if not exists and no store is recorded
+func LoadKey(opts KeyOptions) ([]byte, Store, error) {
+	store := opts.Store
+	if store == "" {
+		store = StoreAuto
+	}
+	switch store {
+	case StoreAuto, StoreKeyring, StorePassphrase, StoreKeyfile:
+	default:
+		return nil, store, errors.Errorf("unknown secret store: %s", store)
+	}
+
+	if opts.Recorded != "" {
+		if store != StoreAuto && store != opts.Recorded {
+			return nil, store, errors.Errorf("stored secrets were sealed by the key from %s, but %s is configured", opts.Recorded, store)
+		}
+		key, err := keyFromStore(opts.Recorded, opts, false)
+		if err != nil {
+			return nil, opts.Recorded, errors.Wrapf(err, "stored secrets were sealed by the key from %s", opts.Recorded)
+		}
+		return key, opts.Recorded, nil
+	}
+
+	if store != StoreAuto {
+		key, err := keyFromStore(store, opts, true)
+		return key, store, err
+	}
+	if key, err := keyFromKeyring(opts.Service, opts.Account, true); err == nil {
+		return key, StoreKeyring, nil
+	}
+	if opts.Passphrase != nil {
+		key, err := keyFromPassphrase(opts, true)
+		return key, StorePassphrase, err
+	}
+	key, err := keyFromFile(opts.KeyFile, true)
+	return key, StoreKeyfile, err
+}
+
+// keyFromStore loads the key from store, create is whether to generate the key if not exists
+func keyFromStore(store Store, opts KeyOptions, create bool) ([]byte, error) {
+	switch store {
+	case StoreKeyring:
+		return keyFromKeyring(opts.Service, opts.Account, create)
+	case StorePassphrase:
+		return keyFromPassphrase(opts, create)
+	case StoreKeyfile:
+		return keyFromFile(opts.KeyFile, create)
+	}
+	return nil, errors.Errorf("unknown secret store: %s", store)
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "generate secret key failed")
+	}
+	return key, nil
+}
+
+func keyFromKeyring(service, account string, create bool) ([]byte, error) {
+	encoded, err := keyringGet(service, account)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil || len(key) != KeySize {
+			return nil, errors.New("invalid secret key in keyring")
+		}
+		return key, nil
+	}
+	if !errors.Is(err, ErrKeyNotFound) || !create {
+		return nil, err
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	if err = keyringSet(service, account, []byte(base64.StdEncoding.EncodeToString(key))); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func keyFromFile(file string, create bool) ([]byte, error) {
+	if file == "" {
+		return nil, errors.New("keyfile is not specified")
+	}
+	data, err := os.ReadFile(file)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != KeySize {
+			return nil, errors.Errorf("invalid secret key in %s", file)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "read keyfile failed")
+	}
+	if !create {
+		return nil, errors.Wrapf(ErrKeyNotFound, "keyfile %s", file)
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, errors.Wrap(err, "create dir of keyfile failed")
+	}
+	if err = os.WriteFile(file, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, errors.Wrap(err, "write keyfile failed")
+	}
+	return key, nil
+}
+
+type passphraseMeta struct {
+	Salt  []byte `json:"salt"`
+	Check string `json:"check"`
+}
+
+func keyFromPassphrase(opts KeyOptions, create bool) ([]byte, error) {
+	if opts.Passphrase == nil {
+		return nil, errors.New("passphrase is not given")
+	}
+	if opts.PassphraseFile == "" {
+		return nil, errors.New("passphrase file is not specified")
+	}
+	passphrase, err := opts.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	var (
+		meta   passphraseMeta
+		exists bool
+	)
+	if data, err := os.ReadFile(opts.PassphraseFile); err == nil {
+		if err = json.Unmarshal(data, &meta); err != nil {
+			return nil, errors.Wrap(err, "invalid passphrase file")
+		}
+		exists = true
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "read passphrase file failed")
+	}
+	if !exists && !create {
+		return nil, errors.Wrapf(ErrKeyNotFound, "passphrase file %s", opts.PassphraseFile)
+	}
+	if !exists {
+		meta.Salt = make([]byte, 16)
+		if _, err = rand.Read(meta.Salt); err != nil {
+			return nil, errors.Wrap(err, "generate salt failed")
+		}
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), meta.Salt, 1<<15, 8, 1, KeySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "derive secret key failed")
+	}
+	c, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		if plain, err := c.Open(meta.Check); err != nil || string(plain) != passphraseCheck {
+			return nil, errors.New("wrong passphrase")
+		}
+		return key, nil
+	}
+
+	if meta.Check, err = c.Seal([]byte(passphraseCheck)); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(opts.PassphraseFile), 0700); err != nil {
+		return nil, errors.Wrap(err, "create dir of passphrase file failed")
+	}
+	if err = os.WriteFile(opts.PassphraseFile, data, 0600); err != nil {
+		return nil, errors.Wrap(err, "write passphrase file failed")
+	}
+	return key, nil
+}
@@ -0,0 +1,50 @@
+//go:build darwin
+
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// errCodeItemNotFound exit code of `security` when the item is not found
+const errCodeItemNotFound = 44
+
+func keyringGet(service, account string) ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errCodeItemNotFound {
+			return nil, ErrKeyNotFound
+		}
+		return nil, errors.Wrap(ErrKeyringUnavailable, err.Error())
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+// keyringSet the command is written to the stdin of `security -i`, so that the value isn't exposed by the arguments of process
+func keyringSet(service, account string, value []byte) error {
+	for _, arg := range []string{service, account, string(value)} {
+		if strings.ContainsAny(arg, "\"\\\r\n") {
+			return errors.New("save secret to keychain failed: unsupported character")
+		}
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s \"%s\" -a \"%s\" -w \"%s\"\n", service, account, value))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "save secret to keychain failed: %s", bytes.TrimSpace(out))
+	}
+	// the interactive mode doesn't fail with the command, so the item is read back
+	saved, err := keyringGet(service, account)
+	if err != nil {
+		return errors.Wrap(err, "save secret to keychain failed")
+	}
+	if !bytes.Equal(saved, value) {
+		return errors.New("save secret to keychain failed: item isn't updated")
+	}
+	return nil
+}
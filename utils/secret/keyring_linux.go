@@ -0,0 +1,169 @@
+//go:build linux
+
+package secret
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/pkg/errors"
+)
+
+// Secret Service API: https://specifications.freedesktop.org/secret-service-spec/latest/
+const (
+	ssDest              = "org.freedesktop.secrets"
+	ssPath              = dbus.ObjectPath("/org/freedesktop/secrets")
+	ssDefaultCollection = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	ssNoPrompt          = dbus.ObjectPath("/")
+
+	ssCallTimeout   = 5 * time.Second
+	ssPromptTimeout = 2 * time.Minute
+)
+
+// ssSecret is transferred as the struct (oayays)
+type ssSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+type secretService struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+func openSecretService() (*secretService, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, errors.Wrap(ErrKeyringUnavailable, err.Error())
+	}
+	s := &secretService{conn: conn}
+
+	var output dbus.Variant
+	if err = s.call(ssPath, "org.freedesktop.Secret.Service.OpenSession", "plain", dbus.MakeVariant("")).Store(&output, &s.session); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(ErrKeyringUnavailable, err.Error())
+	}
+	return s, nil
+}
+
+func (s *secretService) call(path dbus.ObjectPath, method string, args ...interface{}) *dbus.Call {
+	ctx, cancel := context.WithTimeout(context.Background(), ssCallTimeout)
+	defer cancel()
+	return s.conn.Object(ssDest, path).CallWithContext(ctx, method, 0, args...)
+}
+
+func (s *secretService) close() {
+	_ = s.call(s.session, "org.freedesktop.Secret.Session.Close").Err
+	_ = s.conn.Close()
+}
+
+func (s *secretService) unlock(paths []dbus.ObjectPath) error {
+	var (
+		unlocked []dbus.ObjectPath
+		prompt   dbus.ObjectPath
+	)
+	if err := s.call(ssPath, "org.freedesktop.Secret.Service.Unlock", paths).Store(&unlocked, &prompt); err != nil {
+		return errors.Wrap(err, "unlock keyring failed")
+	}
+	return s.prompt(prompt)
+}
+
+// prompt asks user to unlock the keyring, and waits until the prompt completed
+func (s *secretService) prompt(path dbus.ObjectPath) error {
+	if path == ssNoPrompt || path == "" {
+		return nil
+	}
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface("org.freedesktop.Secret.Prompt"),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return errors.Wrap(err, "watch prompt failed")
+	}
+	defer func() { _ = s.conn.RemoveMatchSignal(match...) }()
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.call(path, "org.freedesktop.Secret.Prompt.Prompt", "").Err; err != nil {
+		return errors.Wrap(err, "prompt failed")
+	}
+
+	timeout := time.After(ssPromptTimeout)
+	for {
+		select {
+		case signal := <-signals:
+			if signal.Path != path || signal.Name != "org.freedesktop.Secret.Prompt.Completed" {
+				continue
+			}
+			if dismissed, ok := signal.Body[0].(bool); !ok || dismissed {
+				return errors.New("prompt of keyring is dismissed")
+			}
+			return nil
+		case <-timeout:
+			return errors.New("prompt of keyring timeout")
+		}
+	}
+}
+
+func keyringGet(service, account string) ([]byte, error) {
+	s, err := openSecretService()
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	var (
+		attrs            = map[string]string{"service": service, "account": account}
+		unlocked, locked []dbus.ObjectPath
+	)
+	if err = s.call(ssPath, "org.freedesktop.Secret.Service.SearchItems", attrs).Store(&unlocked, &locked); err != nil {
+		return nil, errors.Wrap(err, "search keyring failed")
+	}
+	if len(unlocked) == 0 && len(locked) > 0 {
+		if err = s.unlock(locked); err != nil {
+			return nil, err
+		}
+		unlocked = locked
+	}
+	if len(unlocked) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	var secret ssSecret
+	if err = s.call(unlocked[0], "org.freedesktop.Secret.Item.GetSecret", s.session).Store(&secret); err != nil {
+		return nil, errors.Wrap(err, "get secret from keyring failed")
+	}
+	return secret.Value, nil
+}
+
+func keyringSet(service, account string, value []byte) error {
+	s, err := openSecretService()
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	if err = s.unlock([]dbus.ObjectPath{ssDefaultCollection}); err != nil {
+		return err
+	}
+
+	var (
+		props = map[string]dbus.Variant{
+			"org.freedesktop.Secret.Item.Label":      dbus.MakeVariant(service + " secret key"),
+			"org.freedesktop.Secret.Item.Attributes": dbus.MakeVariant(map[string]string{"service": service, "account": account}),
+		}
+		secret = ssSecret{Session: s.session, Value: value, ContentType: "text/plain"}
+		item   dbus.ObjectPath
+		prompt dbus.ObjectPath
+	)
+	if err = s.call(ssDefaultCollection, "org.freedesktop.Secret.Collection.CreateItem", props, secret, true).Store(&item, &prompt); err != nil {
+		return errors.Wrap(err, "save secret to keyring failed")
+	}
+	return s.prompt(prompt)
+}
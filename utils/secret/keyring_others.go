@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package secret
+
+func keyringGet(_, _ string) ([]byte, error) {
+	return nil, ErrKeyringUnavailable
+}
+
+func keyringSet(_, _ string, _ []byte) error {
+	return ErrKeyringUnavailable
+}
@@ -0,0 +1,170 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// sealedPrefix marks the sealed values, so that the plaintext values stored before can be told apart
+const sealedPrefix = "enc:v1:"
+
+const KeySize = 32
+
+var ErrNoKey = errors.New("secret key is not loaded")
+
+// Cipher seals values by AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, errors.Errorf("secret key must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+func (c *Cipher) Seal(plain []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce failed")
+	}
+	sealed := c.aead.Seal(nonce, nonce, plain, nil)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Open(sealed string) ([]byte, error) {
+	if !IsSealed(sealed) {
+		return nil, errors.New("value is not sealed")
+	}
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode sealed value failed")
+	}
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("sealed value is too short")
+	}
+	plain, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "open sealed value failed, the secret key may be changed")
+	}
+	return plain, nil
+}
+
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+var defaultCipher atomic.Pointer[Cipher]
+
+// SetKey sets the key used by Bytes and String
+func SetKey(key []byte) error {
+	c, err := NewCipher(key)
+	if err != nil {
+		return err
+	}
+	defaultCipher.Store(c)
+	return nil
+}
+
+func seal(plain []byte) (string, error) {
+	c := defaultCipher.Load()
+	if c == nil {
+		return "", ErrNoKey
+	}
+	return c.Seal(plain)
+}
+
+func open(sealed string) ([]byte, error) {
+	c := defaultCipher.Load()
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	return c.Open(sealed)
+}
+
+// Bytes is sealed when marshaled to json, the plaintext one in base64 is accepted when unmarshaling
+type Bytes []byte
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	sealed, err := seal(b)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.WithStack(err)
+	}
+	if s == nil {
+		*b = nil
+		return nil
+	}
+	if !IsSealed(*s) {
+		plain, err := base64.StdEncoding.DecodeString(*s)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		*b = plain
+		return nil
+	}
+	plain, err := open(*s)
+	if err != nil {
+		return err
+	}
+	*b = plain
+	return nil
+}
+
+// String is sealed when marshaled to json, the plaintext one is accepted when unmarshaling
+type String string
+
+func (s String) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return json.Marshal("")
+	}
+	sealed, err := seal([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+func (s *String) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return errors.WithStack(err)
+	}
+	if !IsSealed(str) {
+		*s = String(str)
+		return nil
+	}
+	plain, err := open(str)
+	if err != nil {
+		return err
+	}
+	*s = String(plain)
+	return nil
+}
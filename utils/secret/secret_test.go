@@ -0,0 +1,117 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	Blob  Bytes  `json:"blob"`
+	Token String `json:"token,omitempty"`
+}
+
+func TestSealRecord(t *testing.T) {
+	key, _, err := LoadKey(KeyOptions{Store: StoreKeyfile, KeyFile: filepath.Join(t.TempDir(), "secret.key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = SetKey(key); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(record{Blob: []byte("blob"), Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(`"token":"token"`)) || bytes.Contains(data, []byte("YmxvYg")) {
+		t.Errorf("secrets are not sealed: %s", data)
+	}
+
+	var r record
+	if err = json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if string(r.Blob) != "blob" || r.Token != "token" {
+		t.Errorf("record = %+v", r)
+	}
+
+	// plaintext stored before
+	if err = json.Unmarshal([]byte(`{"blob":"YmxvYg==","token":"token"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if string(r.Blob) != "blob" || r.Token != "token" {
+		t.Errorf("plaintext record = %+v", r)
+	}
+}
+
+func TestKeyfileReused(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret.key")
+	key1, _, err := LoadKey(KeyOptions{Store: StoreKeyfile, KeyFile: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _, err := LoadKey(KeyOptions{Store: StoreKeyfile, KeyFile: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key1, key2) {
+		t.Error("key should be read from keyfile")
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	opts := KeyOptions{
+		Store:          StorePassphrase,
+		PassphraseFile: filepath.Join(t.TempDir(), "passphrase.json"),
+		Passphrase:     func() (string, error) { return "correct horse", nil },
+	}
+	key1, _, err := LoadKey(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _, err := LoadKey(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key1, key2) {
+		t.Error("same passphrase should derive same key")
+	}
+
+	opts.Passphrase = func() (string, error) { return "wrong", nil }
+	if _, _, err = LoadKey(opts); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("err = %v, expected wrong passphrase", err)
+	}
+}
+
+func TestRecordedStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret.key")
+	key1, used, err := LoadKey(KeyOptions{Store: StoreKeyfile, KeyFile: file})
+	if err != nil || used != StoreKeyfile {
+		t.Fatal(used, err)
+	}
+
+	// the recorded store is used in auto mode
+	key2, used, err := LoadKey(KeyOptions{Store: StoreAuto, KeyFile: file, Recorded: StoreKeyfile})
+	if err != nil || used != StoreKeyfile || !bytes.Equal(key1, key2) {
+		t.Fatalf("key from recorded store: %s %v", used, err)
+	}
+
+	// another store is configured
+	if _, _, err = LoadKey(KeyOptions{Store: StorePassphrase, KeyFile: file, Recorded: StoreKeyfile}); err == nil {
+		t.Error("expected error if configured store differs from recorded")
+	}
+
+	// the key is lost, a new one must not be generated
+	missing := filepath.Join(t.TempDir(), "secret.key")
+	if _, _, err = LoadKey(KeyOptions{Store: StoreAuto, KeyFile: missing, Recorded: StoreKeyfile}); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	if _, err = os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("keyfile should not be generated")
+	}
+}
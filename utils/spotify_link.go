@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/zmb3/spotify/v2"
+)
+
+type SpotifyLinkType string
+
+const (
+	SpotifyLinkTrack    SpotifyLinkType = "track"
+	SpotifyLinkAlbum    SpotifyLinkType = "album"
+	SpotifyLinkPlaylist SpotifyLinkType = "playlist"
+	SpotifyLinkArtist   SpotifyLinkType = "artist"
+	SpotifyLinkShow     SpotifyLinkType = "show"
+	SpotifyLinkEpisode  SpotifyLinkType = "episode"
+)
+
+var (
+	spotifyIdRegex   = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+	spotifyLinkTypes = map[SpotifyLinkType]bool{
+		SpotifyLinkTrack:    true,
+		SpotifyLinkAlbum:    true,
+		SpotifyLinkPlaylist: true,
+		SpotifyLinkArtist:   true,
+		SpotifyLinkShow:     true,
+		SpotifyLinkEpisode:  true,
+	}
+)
+
+var ErrInvalidSpotifyLink = errors.New("invalid spotify link")
+
+// ParseSpotifyLink parses the reverse of WebURLOf*, supports:
+//
+//	https://open.spotify.com/track/{id}?si=xxx
+//	https://open.spotify.com/intl-ja/album/{id}
+//	spotify:playlist:{id}
+//	spotify:user:{user}:playlist:{id}
+func ParseSpotifyLink(link string) (SpotifyLinkType, spotify.ID, error) {
+	link = strings.TrimSpace(link)
+
+	var segments []string
+	if strings.HasPrefix(link, "spotify:") {
+		segments = strings.Split(strings.TrimPrefix(link, "spotify:"), ":")
+	} else {
+		if !strings.Contains(link, "://") {
+			link = "https://" + link
+		}
+		u, err := url.Parse(link)
+		if err != nil || u.Hostname() != "open.spotify.com" {
+			return "", "", ErrInvalidSpotifyLink
+		}
+		segments = strings.Split(strings.Trim(u.Path, "/"), "/")
+	}
+
+	// skip prefixes such as "intl-ja" and "user/{user}"
+	for i := 0; i+1 < len(segments); i++ {
+		linkType := SpotifyLinkType(segments[i])
+		if !spotifyLinkTypes[linkType] {
+			continue
+		}
+		if id := segments[i+1]; spotifyIdRegex.MatchString(id) {
+			return linkType, spotify.ID(id), nil
+		}
+		return "", "", ErrInvalidSpotifyLink
+	}
+	return "", "", ErrInvalidSpotifyLink
+}
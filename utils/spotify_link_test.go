@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestParseSpotifyLink(t *testing.T) {
+	cases := []struct {
+		link     string
+		linkType SpotifyLinkType
+		id       spotify.ID
+		invalid  bool
+	}{
+		{link: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", linkType: SpotifyLinkTrack, id: "4uLU6hMCjMI75M1A2tKUQC"},
+		{link: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=1a2b3c", linkType: SpotifyLinkTrack, id: "4uLU6hMCjMI75M1A2tKUQC"},
+		{link: "https://open.spotify.com/intl-ja/album/4aawyAB9vmqN3uQ7FjRGTy", linkType: SpotifyLinkAlbum, id: "4aawyAB9vmqN3uQ7FjRGTy"},
+		{link: "https://open.spotify.com/intl-ja/album/4aawyAB9vmqN3uQ7FjRGTy?si=1a2b3c", linkType: SpotifyLinkAlbum, id: "4aawyAB9vmqN3uQ7FjRGTy"},
+		{link: " open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M/ ", linkType: SpotifyLinkPlaylist, id: "37i9dQZF1DXcBWIGoYBM5M"},
+		{link: "https://open.spotify.com/user/someone/playlist/37i9dQZF1DXcBWIGoYBM5M", linkType: SpotifyLinkPlaylist, id: "37i9dQZF1DXcBWIGoYBM5M"},
+		{link: "https://open.spotify.com/artist/0OdUWJ0sBjDrqHygGUXeCF", linkType: SpotifyLinkArtist, id: "0OdUWJ0sBjDrqHygGUXeCF"},
+		{link: "https://open.spotify.com/show/2MAi0BvDc6GTFvKFPXnkCL", linkType: SpotifyLinkShow, id: "2MAi0BvDc6GTFvKFPXnkCL"},
+		{link: "https://open.spotify.com/episode/512ojhOuo1ktJprKbVcKyQ", linkType: SpotifyLinkEpisode, id: "512ojhOuo1ktJprKbVcKyQ"},
+		{link: "spotify:track:4uLU6hMCjMI75M1A2tKUQC", linkType: SpotifyLinkTrack, id: "4uLU6hMCjMI75M1A2tKUQC"},
+		{link: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", linkType: SpotifyLinkAlbum, id: "4aawyAB9vmqN3uQ7FjRGTy"},
+		{link: "spotify:user:someone:playlist:37i9dQZF1DXcBWIGoYBM5M", linkType: SpotifyLinkPlaylist, id: "37i9dQZF1DXcBWIGoYBM5M"},
+		{link: "", invalid: true},
+		{link: "spotify:", invalid: true},
+		{link: "spotify:track", invalid: true},
+		{link: "spotify:track:tooshort", invalid: true},
+		{link: "spotify:user:someone", invalid: true},
+		{link: "https://example.com/track/4uLU6hMCjMI75M1A2tKUQC", invalid: true},
+		{link: "https://open.spotify.com/genre/4uLU6hMCjMI75M1A2tKUQC", invalid: true},
+		{link: "https://open.spotify.com/track/", invalid: true},
+		{link: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC!", invalid: true},
+		{link: "://", invalid: true},
+	}
+	for _, c := range cases {
+		linkType, id, err := ParseSpotifyLink(c.link)
+		if c.invalid {
+			if err != ErrInvalidSpotifyLink {
+				t.Errorf("%q = (%s, %s, %v), expected invalid", c.link, linkType, id, err)
+			}
+			continue
+		}
+		if err != nil || linkType != c.linkType || id != c.id {
+			t.Errorf("%q = (%s, %s, %v), expected (%s, %s)", c.link, linkType, id, err, c.linkType, c.id)
+		}
+	}
+}
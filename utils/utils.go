@@ -3,12 +3,15 @@ package utils
 import (
 	"embed"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"io/fs"
 	"math"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/buger/jsonparser"
 	"github.com/go-musicfox/spotifox/internal/configs"
@@ -54,6 +57,20 @@ func LoadIniConfig() {
 	configs.ConfigRegistry = configs.NewRegistryFromIniFile(configFile)
 }
 
+// LoadProfileIniConfig loads the settings in spotifox.ini, overridden by the ones in spotifox_<profile>.ini if exists
+func LoadProfileIniConfig(profile string) {
+	projectDir := GetLocalDataDir()
+	configs.ConfigRegistry = configs.NewRegistryFromIniFiles(
+		path.Join(projectDir, types.AppIniFile),
+		ProfileIniFile(profile),
+	)
+}
+
+// ProfileIniFile path of the settings of profile
+func ProfileIniFile(profile string) string {
+	return path.Join(GetLocalDataDir(), fmt.Sprintf(types.AppProfileIniFile, profile))
+}
+
 func CheckUpdate() (bool, string) {
 	response, err := http.Get(types.AppCheckUpdateUrl)
 	if err != nil {
@@ -144,6 +161,14 @@ func WebURLOfAlbum(artistId spotify.ID) string {
 	return "https://open.spotify.com/album/" + string(artistId)
 }
 
+func WebURLOfShow(showId spotify.ID) string {
+	return "https://open.spotify.com/show/" + string(showId)
+}
+
+func WebURLOfEpisode(episodeId spotify.ID) string {
+	return "https://open.spotify.com/episode/" + string(episodeId)
+}
+
 func WebURLOfLibrary() string {
 	return "https://open.spotify.com/collection/tracks"
 }
@@ -161,6 +186,26 @@ func PicURLOfSong(song *spotify.FullTrack) (url string) {
 	return
 }
 
+// FormatCount format count with thousands separators, e.g. 1234567 => 1,234,567
+func FormatCount(count uint) string {
+	str := strconv.FormatUint(uint64(count), 10)
+	if len(str) <= 3 {
+		return str
+	}
+	var builder strings.Builder
+	head := len(str) % 3
+	if head > 0 {
+		builder.WriteString(str[:head])
+	}
+	for i := head; i < len(str); i += 3 {
+		if builder.Len() > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(str[i : i+3])
+	}
+	return builder.String()
+}
+
 func FileOrDirExists(filename string) bool {
 	_, err := os.Stat(filename)
 	return !os.IsNotExist(err)